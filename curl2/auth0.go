@@ -0,0 +1,90 @@
+package curl2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/go-retryablehttp"
+	"strings"
+)
+
+// auth0Config holds the Auth0 application used to request tokens.
+type auth0Config struct {
+	ClientID     string
+	ClientSecret string
+	Domain       string
+}
+
+// newAuth0Config resolves the provider auth0 block. Every setting falls back to its environment variable when it
+// is not set in the block.
+func newAuth0Config(m auth0Model) auth0Config {
+	return auth0Config{
+		ClientID:     valueOrEnv(m.ClientID, "AUTH0_CLIENT_ID"),
+		ClientSecret: valueOrEnv(m.ClientSecret, "AUTH0_CLIENT_SECRET"),
+		Domain:       valueOrEnv(m.Domain, "AUTH0_DOMAIN"),
+	}
+}
+
+func (c auth0Config) validate() error {
+	switch {
+	case c.ClientID == "":
+		return fmt.Errorf("client id is missing in provider auth0 block")
+	case c.ClientSecret == "":
+		return fmt.Errorf("client secret is missing in provider auth0 block")
+	case c.Domain == "":
+		return fmt.Errorf("domain is missing in provider auth0 block")
+	}
+	return nil
+}
+
+// auth0TokenRequest holds the per token parameters of an Auth0 client credentials request.
+type auth0TokenRequest struct {
+	Audience     string
+	Organization string
+	ExtraParams  map[string]string
+}
+
+// auth0Token requests a client credentials token. Auth0 error responses are returned with their
+// error and error_description.
+func auth0Token(ctx context.Context, client *HttpClient, config auth0Config, tokenRequest auth0TokenRequest) (*oauth2Token, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	if tokenRequest.Audience == "" {
+		return nil, fmt.Errorf("audience must be provided")
+	}
+
+	params := map[string]string{}
+	for key, value := range tokenRequest.ExtraParams {
+		params[key] = value
+	}
+	params["client_id"] = config.ClientID
+	params["client_secret"] = config.ClientSecret
+	params["audience"] = tokenRequest.Audience
+	params["grant_type"] = grantTypeClientCredentials
+	if tokenRequest.Organization != "" {
+		params["organization"] = tokenRequest.Organization
+	}
+
+	payload, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling json to get auth0 token: %w", err)
+	}
+
+	request, err := retryablehttp.NewRequestWithContext(ctx, "POST", strings.TrimSuffix(config.Domain, "/")+"/oauth/token", payload)
+	if err != nil {
+		return nil, fmt.Errorf("error generating new request auth0 token: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	res, body, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error sending HTTP request to get auth0 token: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, tokenError(res.Status, body)
+	}
+
+	return parseTokenResponse(body)
+}
@@ -0,0 +1,250 @@
+package curl2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/go-retryablehttp"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	azureCredentialClientSecret      = "ClientSecret"
+	azureCredentialClientCertificate = "ClientCertificate"
+	azureCredentialWorkloadIdentity  = "WorkloadIdentity"
+	azureCredentialManagedIdentity   = "ManagedIdentity"
+
+	defaultMSIEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+)
+
+// azureADConfig holds the Azure AD identity used to request tokens.
+type azureADConfig struct {
+	CredentialType            string
+	ClientID                  string
+	ClientSecret              string
+	TenantID                  string
+	ClientCertificatePath     string
+	ClientCertificatePassword string
+	FederatedTokenFile        string
+	MSIEndpoint               string
+	AuthorityHost             string
+}
+
+// azureADIdentity describes the identity a token was issued to.
+type azureADIdentity struct {
+	CredentialType string
+	ClientID       string
+	TenantID       string
+	ObjectID       string
+}
+
+// newAzureADConfig resolves the provider azure_ad block. Every setting falls back to its environment variable
+// when it is not set in the block.
+func newAzureADConfig(m azureADModel) azureADConfig {
+	return azureADConfig{
+		CredentialType:            valueOrEnv(m.CredentialType, "AZURE_CREDENTIAL_TYPE"),
+		ClientID:                  valueOrEnv(m.ClientID, "AZURE_CLIENT_ID"),
+		ClientSecret:              valueOrEnv(m.ClientSecret, "AZURE_CLIENT_SECRET"),
+		TenantID:                  valueOrEnv(m.TenantID, "AZURE_TENANT_ID"),
+		ClientCertificatePath:     valueOrEnv(m.ClientCertificatePath, "AZURE_CLIENT_CERTIFICATE_PATH"),
+		ClientCertificatePassword: valueOrEnv(m.ClientCertificatePassword, "AZURE_CLIENT_CERTIFICATE_PASSWORD"),
+		FederatedTokenFile:        valueOrEnv(m.FederatedTokenFile, "AZURE_FEDERATED_TOKEN_FILE"),
+		MSIEndpoint:               valueOrEnv(m.MSIEndpoint, "AZURE_MSI_ENDPOINT"),
+		AuthorityHost:             valueOrEnv(m.AuthorityHost, "AZURE_AUTHORITY_HOST"),
+	}
+}
+
+// credentialType returns the configured credential type, defaulting to a client secret.
+func (c azureADConfig) credentialType() string {
+	if c.CredentialType == "" {
+		return azureCredentialClientSecret
+	}
+	return c.CredentialType
+}
+
+// validate checks that the settings required by the credential type are present.
+func (c azureADConfig) validate() error {
+	switch c.credentialType() {
+	case azureCredentialClientSecret:
+		if c.ClientSecret == "" {
+			return fmt.Errorf("client secret is missing in provider azure_ad block")
+		}
+	case azureCredentialClientCertificate:
+		if c.ClientCertificatePath == "" {
+			return fmt.Errorf("client certificate path is missing in provider azure_ad block")
+		}
+	case azureCredentialWorkloadIdentity:
+		if c.FederatedTokenFile == "" {
+			return fmt.Errorf("federated token file is missing in provider azure_ad block")
+		}
+	case azureCredentialManagedIdentity:
+		// The client id is only needed for a user assigned identity.
+		return nil
+	default:
+		return fmt.Errorf("credential type must be one of %s, %s, %s or %s, got: %s",
+			azureCredentialClientSecret, azureCredentialClientCertificate, azureCredentialWorkloadIdentity, azureCredentialManagedIdentity, c.CredentialType)
+	}
+
+	switch {
+	case c.ClientID == "":
+		return fmt.Errorf("client id is missing in provider azure_ad block")
+	case c.TenantID == "":
+		return fmt.Errorf("tenant id is missing in provider azure_ad block")
+	}
+	return nil
+}
+
+// azureADToken requests a token for the given scopes with the credential selected by the configuration.
+// Requests are sent through the provider HTTP client.
+func azureADToken(ctx context.Context, client *HttpClient, config azureADConfig, scopes []string) (azcore.AccessToken, error) {
+	if err := config.validate(); err != nil {
+		return azcore.AccessToken{}, err
+	}
+	if len(scopes) == 0 {
+		return azcore.AccessToken{}, fmt.Errorf("at least one scope must be provided")
+	}
+
+	if config.credentialType() == azureCredentialManagedIdentity {
+		return managedIdentityToken(ctx, client, config, scopes)
+	}
+
+	clientOptions := azcore.ClientOptions{Transport: client.httpClient.HTTPClient}
+	if config.AuthorityHost != "" {
+		clientOptions.Cloud = cloud.Configuration{ActiveDirectoryAuthorityHost: config.AuthorityHost, Services: map[cloud.ServiceName]cloud.ServiceConfiguration{}}
+	}
+
+	var cred azcore.TokenCredential
+	var err error
+	switch config.credentialType() {
+	case azureCredentialClientSecret:
+		cred, err = azidentity.NewClientSecretCredential(config.TenantID, config.ClientID, config.ClientSecret, &azidentity.ClientSecretCredentialOptions{
+			ClientOptions: clientOptions,
+		})
+	case azureCredentialClientCertificate:
+		var data []byte
+		data, err = os.ReadFile(config.ClientCertificatePath)
+		if err != nil {
+			return azcore.AccessToken{}, fmt.Errorf("unable to read client certificate: %w", err)
+		}
+		certs, key, parseErr := azidentity.ParseCertificates(data, []byte(config.ClientCertificatePassword))
+		if parseErr != nil {
+			return azcore.AccessToken{}, fmt.Errorf("unable to parse client certificate: %w", parseErr)
+		}
+		cred, err = azidentity.NewClientCertificateCredential(config.TenantID, config.ClientID, certs, key, &azidentity.ClientCertificateCredentialOptions{
+			ClientOptions: clientOptions,
+		})
+	case azureCredentialWorkloadIdentity:
+		cred, err = azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientOptions: clientOptions,
+			ClientID:      config.ClientID,
+			TenantID:      config.TenantID,
+			TokenFilePath: config.FederatedTokenFile,
+		})
+	}
+	if err != nil {
+		return azcore.AccessToken{}, fmt.Errorf("error creating %s credential: %w", config.credentialType(), err)
+	}
+
+	return cred.GetToken(ctx, policy.TokenRequestOptions{
+		Scopes: scopes,
+	})
+}
+
+// managedIdentityResponse is the token response of the instance metadata service.
+type managedIdentityResponse struct {
+	AccessToken string      `json:"access_token"`
+	ExpiresIn   json.Number `json:"expires_in"`
+	ExpiresOn   json.Number `json:"expires_on"`
+}
+
+// managedIdentityToken requests a token from the instance metadata service (IMDS) endpoint. A client id selects a
+// user assigned identity, otherwise the system assigned identity is used.
+func managedIdentityToken(ctx context.Context, client *HttpClient, config azureADConfig, scopes []string) (azcore.AccessToken, error) {
+	if len(scopes) != 1 {
+		return azcore.AccessToken{}, fmt.Errorf("managed identity tokens are requested for exactly one scope, got: %d", len(scopes))
+	}
+
+	endpoint := config.MSIEndpoint
+	if endpoint == "" {
+		endpoint = defaultMSIEndpoint
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return azcore.AccessToken{}, fmt.Errorf("invalid msi endpoint: %w", err)
+	}
+
+	query := endpointURL.Query()
+	query.Set("api-version", "2018-02-01")
+	query.Set("resource", strings.TrimSuffix(scopes[0], "/.default"))
+	if config.ClientID != "" {
+		query.Set("client_id", config.ClientID)
+	}
+	endpointURL.RawQuery = query.Encode()
+
+	request, err := retryablehttp.NewRequestWithContext(ctx, "GET", endpointURL.String(), nil)
+	if err != nil {
+		return azcore.AccessToken{}, fmt.Errorf("unable to create managed identity token request: %w", err)
+	}
+	request.Header.Set("Metadata", "true")
+
+	res, body, err := client.Do(request)
+	if err != nil {
+		return azcore.AccessToken{}, fmt.Errorf("error sending managed identity token request: %w", err)
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return azcore.AccessToken{}, tokenError(res.Status, body)
+	}
+
+	var response managedIdentityResponse
+	if err = json.Unmarshal(body, &response); err != nil {
+		return azcore.AccessToken{}, fmt.Errorf("unable to decode managed identity token response: %w", err)
+	}
+	if response.AccessToken == "" {
+		return azcore.AccessToken{}, fmt.Errorf("managed identity token response does not contain an access_token")
+	}
+
+	token := azcore.AccessToken{Token: response.AccessToken}
+	if expiresOn, err := response.ExpiresOn.Int64(); err == nil {
+		token.ExpiresOn = time.Unix(expiresOn, 0).UTC()
+	} else if expiresIn, err := response.ExpiresIn.Int64(); err == nil {
+		token.ExpiresOn = time.Now().Add(time.Duration(expiresIn) * time.Second).UTC()
+	}
+
+	return token, nil
+}
+
+// identity reports which identity a token was issued to. The token claims are preferred over the configuration,
+// as a managed identity does not need a configured client id.
+func (c azureADConfig) identity(token string) azureADIdentity {
+	identity := azureADIdentity{
+		CredentialType: c.credentialType(),
+		ClientID:       c.ClientID,
+		TenantID:       c.TenantID,
+	}
+
+	claims, err := decodeJWTClaims(token)
+	if err != nil {
+		return identity
+	}
+	for _, name := range []string{"appid", "azp"} {
+		if value, ok := claims[name].(string); ok && value != "" {
+			identity.ClientID = value
+			break
+		}
+	}
+	if value, ok := claims["tid"].(string); ok && value != "" {
+		identity.TenantID = value
+	}
+	if value, ok := claims["oid"].(string); ok {
+		identity.ObjectID = value
+	}
+
+	return identity
+}
@@ -0,0 +1,148 @@
+package curl2
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManagedIdentityToken(t *testing.T) {
+	cases := []struct {
+		name     string
+		clientID string
+		scopes   []string
+		status   int
+		body     string
+
+		wantQuery     url.Values
+		wantToken     string
+		wantExpiresOn time.Time
+		wantExpiresIn time.Duration
+		wantErr       string
+	}{
+		{
+			name:   "system assigned identity",
+			scopes: []string{"https://management.azure.com/.default"},
+			status: http.StatusOK,
+			body:   `{"access_token":"system-token","expires_in":"86399","expires_on":"1700000000","resource":"https://management.azure.com","token_type":"Bearer"}`,
+			wantQuery: url.Values{
+				"api-version": {"2018-02-01"},
+				"resource":    {"https://management.azure.com"},
+			},
+			wantToken:     "system-token",
+			wantExpiresOn: time.Unix(1700000000, 0).UTC(),
+		},
+		{
+			name:     "user assigned identity",
+			clientID: "00000000-0000-0000-0000-000000000001",
+			scopes:   []string{"https://vault.azure.net/.default"},
+			status:   http.StatusOK,
+			body:     `{"access_token":"user-token","expires_on":1700000000}`,
+			wantQuery: url.Values{
+				"api-version": {"2018-02-01"},
+				"resource":    {"https://vault.azure.net"},
+				"client_id":   {"00000000-0000-0000-0000-000000000001"},
+			},
+			wantToken:     "user-token",
+			wantExpiresOn: time.Unix(1700000000, 0).UTC(),
+		},
+		{
+			name:   "expiry from expires_in",
+			scopes: []string{"https://graph.microsoft.com/.default"},
+			status: http.StatusOK,
+			body:   `{"access_token":"token","expires_in":"3600"}`,
+			wantQuery: url.Values{
+				"api-version": {"2018-02-01"},
+				"resource":    {"https://graph.microsoft.com"},
+			},
+			wantToken:     "token",
+			wantExpiresIn: time.Hour,
+		},
+		{
+			name:    "identity not found",
+			scopes:  []string{"https://management.azure.com/.default"},
+			status:  http.StatusBadRequest,
+			body:    `{"error":"invalid_request","error_description":"Identity not found"}`,
+			wantErr: "token endpoint returned 400 Bad Request: invalid_request: Identity not found",
+		},
+		{
+			name:    "missing access token",
+			scopes:  []string{"https://management.azure.com/.default"},
+			status:  http.StatusOK,
+			body:    `{"expires_in":"3600"}`,
+			wantErr: "managed identity token response does not contain an access_token",
+		},
+		{
+			name:    "invalid response",
+			scopes:  []string{"https://management.azure.com/.default"},
+			status:  http.StatusOK,
+			body:    `<html></html>`,
+			wantErr: "unable to decode managed identity token response",
+		},
+		{
+			name:    "several scopes",
+			scopes:  []string{"https://management.azure.com/.default", "https://vault.azure.net/.default"},
+			wantErr: "managed identity tokens are requested for exactly one scope, got: 2",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if r.Method != http.MethodGet {
+					t.Errorf("method = %s, want GET", r.Method)
+				}
+				if got := r.Header.Get("Metadata"); got != "true" {
+					t.Errorf("Metadata header = %q, want true", got)
+				}
+				if tc.wantQuery != nil && r.URL.Query().Encode() != tc.wantQuery.Encode() {
+					t.Errorf("query = %s, want %s", r.URL.Query().Encode(), tc.wantQuery.Encode())
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			config := azureADConfig{
+				CredentialType: azureCredentialManagedIdentity,
+				ClientID:       tc.clientID,
+				MSIEndpoint:    server.URL + "/metadata/identity/oauth2/token",
+			}
+			token, err := azureADToken(context.Background(), NewClient(ApiClientOpts{}), config, tc.scopes)
+
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tc.wantErr)
+				}
+				if tc.status == 0 && requests != 0 {
+					t.Errorf("%d requests sent, want none", requests)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if requests != 1 {
+				t.Errorf("%d requests sent, want 1", requests)
+			}
+			if token.Token != tc.wantToken {
+				t.Errorf("token = %s, want %s", token.Token, tc.wantToken)
+			}
+			if !tc.wantExpiresOn.IsZero() && !token.ExpiresOn.Equal(tc.wantExpiresOn) {
+				t.Errorf("expires on = %s, want %s", token.ExpiresOn, tc.wantExpiresOn)
+			}
+			if tc.wantExpiresIn > 0 {
+				if expiresIn := time.Until(token.ExpiresOn); expiresIn > tc.wantExpiresIn || expiresIn < tc.wantExpiresIn-time.Minute {
+					t.Errorf("expires in %s, want %s", expiresIn, tc.wantExpiresIn)
+				}
+			}
+		})
+	}
+}
@@ -1,23 +1,69 @@
 package curl2
 
 import (
-	"crypto/tls"
+	"context"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"io"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 )
 
 type ApiClientOpts struct {
-	insecure   bool
-	timeout    int64
-	maxRetries int
-	minDelay   types.Int64
-	maxDelay   types.Int64
+	insecure             bool
+	timeout              int64
+	maxRetries           int
+	minDelay             types.Int64
+	maxDelay             types.Int64
+	retryableStatusCodes []int
+	respectRetryAfter    bool
+	backoff              string
+	jitter               bool
+	idempotentOnly       bool
+
+	connectTimeout        time.Duration
+	tlsHandshakeTimeout   time.Duration
+	responseHeaderTimeout time.Duration
+
+	tls tlsOptions
+
+	logging logOptions
+	// har records the exchanges into an HTTP Archive, nil when no file is configured.
+	har *harRecorder
+}
+
+// requestOverrides replaces provider settings for a single request.
+type requestOverrides struct {
+	timeouts requestTimeouts
+	tls      *tlsOptions
+	insecure bool
+}
+
+// requestTimeouts overrides the provider timeouts for a single request. Zero values keep the provider setting.
+type requestTimeouts struct {
+	connect        time.Duration
+	tlsHandshake   time.Duration
+	responseHeader time.Duration
+	total          time.Duration
+}
+
+// overridesKey identifies the option set of an override client. The tls overrides are identified by their digest.
+type overridesKey struct {
+	timeouts requestTimeouts
+	tls      string
+	insecure bool
 }
 
 type HttpClient struct {
 	httpClient *retryablehttp.Client
+	opts       ApiClientOpts
+
+	// overrideClients caches the clients returned by WithOverrides so that requests with the same overrides
+	// share their transport and its connections.
+	overrideClientsMu sync.Mutex
+	overrideClients   map[overridesKey]*HttpClient
 }
 
 func NewClient(opts ApiClientOpts) *HttpClient {
@@ -32,25 +78,128 @@ func NewClient(opts ApiClientOpts) *HttpClient {
 		retryClient.RetryWaitMax = time.Duration(opts.maxDelay.ValueInt64()) * time.Millisecond
 	}
 
-	standardClient := retryClient.StandardClient()
-
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: opts.insecure,
-	}
-	standardClient.Transport = &http.Transport{
-		TLSClientConfig: tlsConfig,
-		Proxy:           http.ProxyFromEnvironment,
+	retryClient.CheckRetry = retryPolicy(opts)
+	retryClient.Backoff = backoffPolicy(opts)
+	// Hand back the last response once retries are exhausted instead of an error.
+	retryClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	// Requests are logged through tflog by the transport instead of the standard logger.
+	retryClient.Logger = nil
+	retryClient.RequestLogHook = func(logger retryablehttp.Logger, req *http.Request, retry int) {
+		logRequestAttempt(logger, req, retry)
+		signRetry(req, retry)
 	}
 
-	tr := &http.Transport{
-		TLSClientConfig: tlsConfig,
-		Proxy:           http.ProxyFromEnvironment,
+	retryClient.HTTPClient = &http.Client{
+		Transport: &loggingTransport{transport: harRecording(newTransport(opts), opts), opts: opts.logging},
+		Timeout:   time.Duration(opts.timeout) * time.Millisecond,
 	}
-	standardClient.Transport = tr
 
 	client := HttpClient{
-		httpClient: retryClient,
+		httpClient:      retryClient,
+		opts:            opts,
+		overrideClients: map[overridesKey]*HttpClient{},
 	}
 
 	return &client
 }
+
+func newTransport(opts ApiClientOpts) *http.Transport {
+	connectTimeout := 30 * time.Second
+	if opts.connectTimeout > 0 {
+		connectTimeout = opts.connectTimeout
+	}
+
+	tlsHandshakeTimeout := 10 * time.Second
+	if opts.tlsHandshakeTimeout > 0 {
+		tlsHandshakeTimeout = opts.tlsHandshakeTimeout
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   connectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       opts.tls.tlsConfig(opts.insecure),
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ResponseHeaderTimeout: opts.responseHeaderTimeout,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// harRecording wraps the transport to record its exchanges when an HTTP Archive file is configured.
+func harRecording(transport http.RoundTripper, opts ApiClientOpts) http.RoundTripper {
+	if opts.har == nil {
+		return transport
+	}
+	return &harTransport{transport: transport, recorder: opts.har, opts: opts.logging}
+}
+
+// WithOverrides returns a client that applies the given settings on top of the provider configuration.
+// The total timeout replaces the provider timeout of each attempt, callers also bound the request context
+// with it to cover retries. Clients are cached per set of overrides and reused by later requests.
+func (c *HttpClient) WithOverrides(overrides requestOverrides) *HttpClient {
+	timeouts := overrides.timeouts
+	if timeouts == (requestTimeouts{}) && overrides.tls == nil && !overrides.insecure {
+		return c
+	}
+
+	key := overridesKey{timeouts: timeouts, insecure: overrides.insecure}
+	if overrides.tls != nil {
+		key.tls = overrides.tls.digest()
+	}
+
+	c.overrideClientsMu.Lock()
+	defer c.overrideClientsMu.Unlock()
+	if client, ok := c.overrideClients[key]; ok {
+		return client
+	}
+
+	opts := c.opts
+	if overrides.tls != nil {
+		opts.tls = opts.tls.merge(*overrides.tls)
+	}
+	if overrides.insecure {
+		opts.insecure = true
+	}
+	if timeouts.connect > 0 {
+		opts.connectTimeout = timeouts.connect
+	}
+	if timeouts.tlsHandshake > 0 {
+		opts.tlsHandshakeTimeout = timeouts.tlsHandshake
+	}
+	if timeouts.responseHeader > 0 {
+		opts.responseHeaderTimeout = timeouts.responseHeader
+	}
+	if timeouts.total > 0 {
+		opts.timeout = timeouts.total.Milliseconds()
+	}
+
+	client := NewClient(opts)
+	c.overrideClients[key] = client
+	return client
+}
+
+// Do sends the request and reads back the whole response body.
+func (c *HttpClient) Do(req *retryablehttp.Request) (*http.Response, []byte, error) {
+	ctx := context.WithValue(req.Context(), requestMethodKey{}, req.Method)
+	req = req.WithContext(context.WithValue(ctx, requestAttemptKey{}, &requestAttempt{}))
+
+	r, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return r, nil, err
+	}
+
+	return r, body, nil
+}
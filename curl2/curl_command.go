@@ -0,0 +1,343 @@
+package curl2
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// curlCommand is a request described by a curl command line.
+type curlCommand struct {
+	method   string
+	url      string
+	headers  map[string]string
+	body     *requestBody
+	username string
+	password string
+	insecure bool
+}
+
+// curlFlags maps the supported curl flags to their canonical long name. Flags that only change how curl
+// prints the response are accepted and ignored, and redirects are always followed, as with --location.
+var curlFlags = map[string]string{
+	"-X":            "--request",
+	"--request":     "--request",
+	"-H":            "--header",
+	"--header":      "--header",
+	"-d":            "--data",
+	"--data":        "--data",
+	"--data-raw":    "--data-raw",
+	"--data-binary": "--data-binary",
+	"-u":            "--user",
+	"--user":        "--user",
+	"-F":            "--form",
+	"--form":        "--form",
+	"--json":        "--json",
+	"-k":            "--insecure",
+	"--insecure":    "--insecure",
+	"--url":         "--url",
+	"-G":            "--get",
+	"--get":         "--get",
+	"--compressed":  "--compressed",
+	"-s":            "--silent",
+	"--silent":      "--silent",
+	"-S":            "--show-error",
+	"--show-error":  "--show-error",
+	"-L":            "--location",
+	"--location":    "--location",
+}
+
+// curlFlagsWithValue lists the canonical flags that take a value.
+var curlFlagsWithValue = map[string]bool{
+	"--request":     true,
+	"--header":      true,
+	"--data":        true,
+	"--data-raw":    true,
+	"--data-binary": true,
+	"--user":        true,
+	"--form":        true,
+	"--json":        true,
+	"--url":         true,
+}
+
+// parseCurlCommand parses a curl command line, as pasted from API documentation, into a request.
+func parseCurlCommand(command string) (*curlCommand, error) {
+	args, err := splitCommandLine(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) > 0 && args[0] == "curl" {
+		args = args[1:]
+	}
+
+	parsed := &curlCommand{headers: map[string]string{}}
+	var data []string
+	var jsonData []string
+	formFields := map[string]string{}
+	formFiles := map[string]string{}
+	isForm, isGet := false, false
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			if parsed.url != "" {
+				return nil, fmt.Errorf("only one URL is supported, got %q and %q", parsed.url, arg)
+			}
+			parsed.url = arg
+			continue
+		}
+
+		// Short flags can be combined, as in -sSL, and take their value from the rest of the argument, as in -XPOST.
+		var flags []string
+		var attached string
+		if strings.HasPrefix(arg, "--") {
+			flags = []string{arg}
+		} else {
+			for j := 1; j < len(arg); j++ {
+				flag := "-" + arg[j:j+1]
+				flags = append(flags, flag)
+				if curlFlagsWithValue[curlFlags[flag]] {
+					attached = arg[j+1:]
+					break
+				}
+			}
+		}
+
+		for _, flag := range flags {
+			name, ok := curlFlags[flag]
+			if !ok {
+				return nil, fmt.Errorf("unsupported curl flag %s", flag)
+			}
+
+			var value string
+			if curlFlagsWithValue[name] {
+				switch {
+				case attached != "":
+					value = attached
+				case i+1 < len(args):
+					i++
+					value = args[i]
+				default:
+					return nil, fmt.Errorf("curl flag %s requires a value", flag)
+				}
+			}
+
+			switch name {
+			case "--request":
+				parsed.method = value
+			case "--header":
+				headerName, headerValue, found := strings.Cut(value, ":")
+				if !found {
+					return nil, fmt.Errorf("invalid header %q: expected name: value", value)
+				}
+				parsed.headers[strings.TrimSpace(headerName)] = strings.TrimSpace(headerValue)
+			case "--data", "--data-binary", "--json":
+				content, err := curlDataValue(value, name != "--data-binary")
+				if err != nil {
+					return nil, err
+				}
+				if name == "--json" {
+					jsonData = append(jsonData, content)
+				} else {
+					data = append(data, content)
+				}
+			case "--data-raw":
+				data = append(data, value)
+			case "--user":
+				username, password, found := strings.Cut(value, ":")
+				if !found {
+					return nil, fmt.Errorf("invalid user %q: expected user:password", value)
+				}
+				parsed.username, parsed.password = username, password
+			case "--form":
+				if err := addCurlFormPart(value, formFields, formFiles); err != nil {
+					return nil, err
+				}
+				isForm = true
+			case "--insecure":
+				parsed.insecure = true
+			case "--url":
+				if parsed.url != "" {
+					return nil, fmt.Errorf("only one URL is supported, got %q and %q", parsed.url, value)
+				}
+				parsed.url = value
+			case "--get":
+				isGet = true
+			case "--compressed":
+				// The client already asks for gzip responses and decodes them.
+			}
+		}
+	}
+
+	if parsed.url == "" {
+		return nil, fmt.Errorf("curl command has no URL")
+	}
+	if isForm && (len(data) > 0 || len(jsonData) > 0) {
+		return nil, fmt.Errorf("-F cannot be combined with -d or --json")
+	}
+	if isForm && isGet {
+		return nil, fmt.Errorf("-F cannot be combined with -G")
+	}
+
+	switch {
+	case isGet:
+		if query := strings.Join(append(data, jsonData...), "&"); query != "" {
+			if strings.Contains(parsed.url, "?") {
+				parsed.url += "&" + query
+			} else {
+				parsed.url += "?" + query
+			}
+		}
+	case len(jsonData) > 0:
+		// curl sends every --json value one after the other, along with any -d value.
+		parsed.body = &requestBody{
+			data:        []byte(strings.Join(data, "&") + strings.Join(jsonData, "")),
+			contentType: "application/json",
+		}
+		if !hasHeader(parsed.headers, "Accept") {
+			parsed.headers["Accept"] = "application/json"
+		}
+	case len(data) > 0:
+		parsed.body = &requestBody{
+			data:        []byte(strings.Join(data, "&")),
+			contentType: "application/x-www-form-urlencoded",
+		}
+	case isForm:
+		content, contentType, err := encodeMultipart(formFields, formFiles)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode -F form: %w", err)
+		}
+		parsed.body = &requestBody{
+			data:        content,
+			contentType: contentType,
+		}
+	}
+
+	if parsed.method == "" {
+		parsed.method = http.MethodGet
+		if parsed.body != nil {
+			parsed.method = http.MethodPost
+		}
+	}
+
+	return parsed, nil
+}
+
+// curlDataValue returns the value of a data flag, reading it from a file when it starts with @. Like curl,
+// line breaks are removed from file contents unless binary is requested.
+func curlDataValue(value string, stripNewlines bool) (string, error) {
+	if !strings.HasPrefix(value, "@") {
+		return value, nil
+	}
+
+	content, err := os.ReadFile(value[1:])
+	if err != nil {
+		return "", fmt.Errorf("unable to read data file: %w", err)
+	}
+	if stripNewlines {
+		return strings.NewReplacer("\r", "", "\n", "").Replace(string(content)), nil
+	}
+	return string(content), nil
+}
+
+// addCurlFormPart adds a -F value, name=value, name=@file for a file or name=<file for a field read from a file.
+func addCurlFormPart(value string, fields map[string]string, files map[string]string) error {
+	name, content, found := strings.Cut(value, "=")
+	if !found {
+		return fmt.Errorf("invalid form part %q: expected name=value", value)
+	}
+
+	switch {
+	case strings.HasPrefix(content, "@"):
+		if strings.Contains(content, ";") {
+			return fmt.Errorf("invalid form part %q: options such as ;type= are not supported", value)
+		}
+		files[name] = content[1:]
+	case strings.HasPrefix(content, "<"):
+		fileContent, err := os.ReadFile(content[1:])
+		if err != nil {
+			return fmt.Errorf("unable to read form file: %w", err)
+		}
+		fields[name] = string(fileContent)
+	default:
+		fields[name] = content
+	}
+	return nil
+}
+
+// hasHeader reports whether a header is set, ignoring the case of its name.
+func hasHeader(headers map[string]string, name string) bool {
+	for headerName := range headers {
+		if strings.EqualFold(headerName, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitCommandLine splits a command line into arguments following POSIX shell quoting. Single quotes keep
+// their content as is, double quotes and backslashes escape characters, and a backslash before a line
+// break continues the command on the next line.
+func splitCommandLine(command string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case c == '\\':
+			if i+1 >= len(command) {
+				return nil, fmt.Errorf("curl command ends with a backslash")
+			}
+			i++
+			if command[i] == '\n' {
+				continue
+			}
+			if command[i] == '\r' && i+1 < len(command) && command[i+1] == '\n' {
+				i++
+				continue
+			}
+			current.WriteByte(command[i])
+			inArg = true
+		case c == '\'':
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end == -1 {
+				return nil, fmt.Errorf("curl command has an unterminated single quote")
+			}
+			current.WriteString(command[i+1 : i+1+end])
+			i += end + 1
+			inArg = true
+		case c == '"':
+			i++
+			for ; i < len(command) && command[i] != '"'; i++ {
+				if command[i] == '\\' && i+1 < len(command) && strings.IndexByte("\\\"$`\n", command[i+1]) != -1 {
+					i++
+					if command[i] == '\n' {
+						continue
+					}
+				}
+				current.WriteByte(command[i])
+			}
+			if i >= len(command) {
+				return nil, fmt.Errorf("curl command has an unterminated double quote")
+			}
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
@@ -1,17 +1,13 @@
 package curl2
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"io"
-	"net/http"
-	"os"
 )
 
 var (
@@ -24,27 +20,31 @@ func NewAuth0TokenDataSource() datasource.DataSource {
 }
 
 type auth0TokenDataModelRequest struct {
-	Audience types.String `tfsdk:"audience"`
-	Response types.Object `tfsdk:"response"`
+	Audience     types.String `tfsdk:"audience"`
+	Organization types.String `tfsdk:"organization"`
+	ExtraParams  types.Map    `tfsdk:"extra_params"`
+	Response     types.Object `tfsdk:"response"`
 }
 
-type tokenRequestBody struct {
-	ClientID     string `json:"client_id"`
-	ClientSecret string `json:"client_secret"`
-	Audience     string `json:"audience"`
-	GrantType    string `json:"grant_type"`
+type auth0TokenDataSource struct {
+	providerData *curl2ProviderData
 }
 
-type tokenResponseBody struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
+func auth0TokenResponseAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"token":      types.StringType,
+		"token_type": types.StringType,
+		"expires_in": types.Int64Type,
+		"expires_at": types.StringType,
+		"scope":      types.StringType,
+	}
 }
-type auth0TokenDataSource struct{}
 
 func (a *auth0TokenDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
+	a.providerData = req.ProviderData.(*curl2ProviderData)
 }
 
 func (a *auth0TokenDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -59,12 +59,20 @@ func (a *auth0TokenDataSource) Schema(ctx context.Context, req datasource.Schema
 				Description: "The audience for the token, which is your API. Example: \"https://xyz.com\"",
 				Required:    true,
 			},
+			"organization": schema.StringAttribute{
+				Description: "Organization name or ID the token is requested for.",
+				Optional:    true,
+			},
+			"extra_params": schema.MapAttribute{
+				Description: "Additional parameters sent in the token request body.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
 			"response": schema.ObjectAttribute{
-				AttributeTypes: map[string]attr.Type{
-					"token": types.StringType,
-				},
-				Description: "Token response.",
-				Computed:    true,
+				AttributeTypes: auth0TokenResponseAttrTypes(),
+				Description:    "Token response. `expires_at` is in RFC 3339 format.",
+				Computed:       true,
+				Sensitive:      true,
 			},
 		},
 	}
@@ -79,117 +87,88 @@ func (a *auth0TokenDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		return
 	}
 
-	clientID := os.Getenv("AUTH0_CLIENT_ID")
-	clientSecret := os.Getenv("AUTH0_CLIENT_SECRET")
-	domain := os.Getenv("AUTH0_DOMAIN")
+	config.Response, diags = auth0TokenResponse(ctx, a.providerData, config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// auth0TokenResponse fetches a token for the audience with the provider auth0 configuration and
+// builds the `response` object shared by the data source and the ephemeral resource.
+func auth0TokenResponse(ctx context.Context, providerData *curl2ProviderData, config auth0TokenDataModelRequest) (types.Object, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	response := types.ObjectNull(auth0TokenResponseAttrTypes())
 
-	if clientID == "" {
-		resp.Diagnostics.AddAttributeError(
+	if providerData.auth0.ClientID == "" {
+		diags.AddAttributeError(
 			path.Root("client_id"),
 			"Missing Auth0 Client ID",
-			"The data source cannot get the token as client id is missing in provider auth0 block",
+			"The token cannot be fetched as client id is missing in provider auth0 block",
 		)
 	}
 
-	if clientSecret == "" {
-		resp.Diagnostics.AddAttributeError(
+	if providerData.auth0.ClientSecret == "" {
+		diags.AddAttributeError(
 			path.Root("client_secret"),
 			"Missing Auth0 Client Secret",
-			"The data source cannot get the token as client secret is missing in provider auth0 block",
+			"The token cannot be fetched as client secret is missing in provider auth0 block",
 		)
 	}
 
-	if domain == "" {
-		resp.Diagnostics.AddAttributeError(
+	if providerData.auth0.Domain == "" {
+		diags.AddAttributeError(
 			path.Root("domain"),
 			"Missing Auth0 Domain",
-			"The data source cannot get the token as domain is missing in provider auth0 block",
+			"The token cannot be fetched as domain is missing in provider auth0 block",
 		)
 	}
 
 	if config.Audience.ValueString() == "" {
-		resp.Diagnostics.AddAttributeError(
+		diags.AddAttributeError(
 			path.Root("audience"),
 			"Missing Auth0 Audience",
-			"The data source cannot get the token as audience is missing",
+			"The token cannot be fetched as audience is missing",
 		)
 	}
 
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	url := domain + "/oauth/token"
-
-	payload, err := json.Marshal(tokenRequestBody{
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
+	tokenRequest := auth0TokenRequest{
 		Audience:     config.Audience.ValueString(),
-		GrantType:    "client_credentials",
-	})
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error marshalling json to get auth0 token",
-			err.Error(),
-		)
-		return
+		Organization: config.Organization.ValueString(),
 	}
-
-	request, err := http.NewRequest("POST", url, bytes.NewReader(payload))
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error generating new request auth0 token",
-			err.Error(),
-		)
-		return
+	if !config.ExtraParams.IsNull() {
+		diags.Append(config.ExtraParams.ElementsAs(ctx, &tokenRequest.ExtraParams, false)...)
 	}
-	request.Header.Add("content-type", "application/json")
 
-	res, err := http.DefaultClient.Do(request)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error sending HTTP request to get auth0 token",
-			err.Error(),
-		)
-		return
+	if diags.HasError() {
+		return response, diags
 	}
 
-	defer res.Body.Close()
-	body, err := io.ReadAll(res.Body)
+	token, err := providerData.auth0Token(ctx, tokenRequest)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading body of response from auth0",
+		diags.AddError(
+			"Error getting auth0 token",
 			err.Error(),
 		)
-		return
+		return response, diags
 	}
 
-	var response tokenResponseBody
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error unmarshalling body of response from auth0",
-			err.Error(),
-		)
-		return
-	}
-
-	config.Response, diags = types.ObjectValue(
-		map[string]attr.Type{
-			"token": types.StringType,
-		},
+	response, objectDiags := types.ObjectValue(
+		auth0TokenResponseAttrTypes(),
 		map[string]attr.Value{
-			"token": types.StringValue(response.AccessToken),
+			"token":      types.StringValue(token.AccessToken),
+			"token_type": types.StringValue(token.TokenType),
+			"expires_in": types.Int64Value(token.expiresInSeconds()),
+			"expires_at": types.StringValue(token.expiresAtString()),
+			"scope":      types.StringValue(token.Scope),
 		},
 	)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	diags = resp.State.Set(ctx, &config)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
+	diags.Append(objectDiags...)
+	return response, diags
 }
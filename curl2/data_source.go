@@ -1,20 +1,23 @@
 package curl2
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
-	"github.com/hashicorp/go-retryablehttp"
-	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"io"
+	"github.com/tidwall/gjson"
+	"net/http"
+	"time"
 )
 
 var (
-	_ datasource.DataSource              = &curl2DataSource{}
-	_ datasource.DataSourceWithConfigure = &curl2DataSource{}
+	_ datasource.DataSource                   = &curl2DataSource{}
+	_ datasource.DataSourceWithConfigure      = &curl2DataSource{}
+	_ datasource.DataSourceWithValidateConfig = &curl2DataSource{}
 )
 
 func NewCurl2DataSource() datasource.DataSource {
@@ -22,19 +25,34 @@ func NewCurl2DataSource() datasource.DataSource {
 }
 
 type curl2DataModelRequest struct {
-	URI               types.String `tfsdk:"uri"`
-	HTTPMethod        types.String `tfsdk:"http_method"`
-	JSON              types.String `tfsdk:"json"`
-	Response          types.Object `tfsdk:"response"`
-	AuthType          types.String `tfsdk:"auth_type"`
-	BearerToken       types.String `tfsdk:"bearer_token"`
-	BasicAuthUsername types.String `tfsdk:"basic_auth_username"`
-	BasicAuthPassword types.String `tfsdk:"basic_auth_password"`
-	Headers           types.Map    `tfsdk:"headers"`
+	curl2RequestModel
+	CurlCommand    types.String `tfsdk:"curl_command"`
+	StatusWarnOnly types.Bool   `tfsdk:"expected_status_warn_only"`
+	Pagination     types.Object `tfsdk:"pagination"`
+	Pages          types.List   `tfsdk:"pages"`
+	Items          types.String `tfsdk:"items"`
+	WaitFor        types.Object `tfsdk:"wait_for"`
+}
+
+type timeoutsModel struct {
+	ConnectMS        types.Int64 `tfsdk:"connect_ms"`
+	TLSHandshakeMS   types.Int64 `tfsdk:"tls_handshake_ms"`
+	ResponseHeaderMS types.Int64 `tfsdk:"response_header_ms"`
+	TotalMS          types.Int64 `tfsdk:"total_ms"`
+}
+
+func (t timeoutsModel) requestTimeouts() requestTimeouts {
+	return requestTimeouts{
+		connect:        time.Duration(t.ConnectMS.ValueInt64()) * time.Millisecond,
+		tlsHandshake:   time.Duration(t.TLSHandshakeMS.ValueInt64()) * time.Millisecond,
+		responseHeader: time.Duration(t.ResponseHeaderMS.ValueInt64()) * time.Millisecond,
+		total:          time.Duration(t.TotalMS.ValueInt64()) * time.Millisecond,
+	}
 }
 
 type curl2DataSource struct {
-	client *HttpClient
+	client       *HttpClient
+	providerData *curl2ProviderData
 }
 
 func (c *curl2DataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -42,58 +60,144 @@ func (c *curl2DataSource) Metadata(ctx context.Context, req datasource.MetadataR
 }
 
 func (c *curl2DataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
-	resp.Schema = schema.Schema{
-		Description: "Fetches the response for the api",
-		Attributes: map[string]schema.Attribute{
-			"uri": schema.StringAttribute{
-				Description: "URI of resource you'd like to retrieve via HTTP(s).",
-				Required:    true,
-			},
-			"http_method": schema.StringAttribute{
-				Description: "HTTP method like GET, POST, PUT, DELETE, PATCH.",
-				Required:    true,
-			},
-			"json": schema.StringAttribute{
-				Description: "JSON object in string format if using POST, PUT or PATCH method.",
-				Optional:    true,
-			},
-			"response": schema.ObjectAttribute{
-				AttributeTypes: map[string]attr.Type{
-					"uri":         types.StringType,
-					"body":        types.StringType,
-					"status_code": types.Int64Type,
-				},
-				Description: "Valued returned by the HTTP request.",
-				Computed:    true,
-			},
-			"auth_type": schema.StringAttribute{
-				Description: "Authentication Type, Bearer or Basic.",
-				Optional:    true,
-			},
-			"bearer_token": schema.StringAttribute{
-				Description: "Bearer Token to be used for Authentication.",
-				Optional:    true,
-				Sensitive:   true,
-			},
-			"basic_auth_username": schema.StringAttribute{
-				Description: "Username to be used for Basic Authentication.",
-				Optional:    true,
-			},
-			"basic_auth_password": schema.StringAttribute{
-				Description: "Password to be used for Authentication.",
-				Optional:    true,
-				Sensitive:   true,
+	specs := requestAttributeSpecs()
+	specs["timeouts"].attributes["total_ms"] = attributeSpec{
+		description: "Overall time allowed for the request in milliseconds, including retries, reading the body and every page of `pagination`. Replaces the provider `timeout_ms` for this request. Defaults to 0, no timeout.",
+		kind:        int64Attribute,
+	}
+
+	attributes := map[string]schema.Attribute{
+		"uri": schema.StringAttribute{
+			Description: "URI of resource you'd like to retrieve via HTTP(s). Required unless `curl_command` is set.",
+			Optional:    true,
+		},
+		"http_method": schema.StringAttribute{
+			Description: "HTTP method like GET, POST, PUT, DELETE, PATCH. Required unless `curl_command` is set.",
+			Optional:    true,
+		},
+		"curl_command": schema.StringAttribute{
+			Description: "curl command line describing the request, instead of `uri` and `http_method`, for example `curl -X POST https://example.com/api -H 'Content-Type: application/json' -d '{\"a\":1}'`. Supports `-X`, `-H`, `-d`, `--data-raw`, `--data-binary`, `-u`, `-F`, `--json`, `-k`, `--url`, `-G` and `--compressed`, while `-s`, `-S` and `-L` are ignored. Other flags are an error. `headers` are added to the headers of the command, and the body attributes and `auth_type` cannot be set when the command sets a body or `-u`.",
+			Optional:    true,
+		},
+		"response": schema.ObjectAttribute{
+			AttributeTypes: responseAttrTypes(),
+			Description:    "Valued returned by the HTTP request.",
+			Computed:       true,
+		},
+		"expected_status_warn_only": schema.BoolAttribute{
+			Description: "Report an unexpected status as a warning instead of failing the read. Defaults to false.",
+			Optional:    true,
+		},
+		"pagination": schema.SingleNestedAttribute{
+			Description: "Follows the pages of a list endpoint. `response` holds the first page, `pages` every page and `items` the items of every page.",
+			Optional:    true,
+			Attributes: map[string]schema.Attribute{
+				"strategy": schema.StringAttribute{
+					Description: "How the next page is found: `link_header` follows the `Link` header with `rel=\"next\"`, `next_url` the URL at `next_url_path`, `cursor` sends the value at `cursor_path` as `cursor_param`, `page` and `offset` increment a query parameter until a page has no items. Links are resolved against the URL of the page after redirects and must keep the scheme and host of `uri`.",
+					Required:    true,
+				},
+				"next_url_path": schema.StringAttribute{
+					Description: "Path of the next page URL in the response body for the `next_url` strategy, for example `$['@odata.nextLink']`.",
+					Optional:    true,
+				},
+				"cursor_path": schema.StringAttribute{
+					Description: "Path of the next cursor in the response body for the `cursor` strategy.",
+					Optional:    true,
+				},
+				"cursor_param": schema.StringAttribute{
+					Description: "Query parameter the cursor is sent in. Defaults to `cursor`.",
+					Optional:    true,
+				},
+				"page_param": schema.StringAttribute{
+					Description: "Query parameter of the page number for the `page` strategy. Defaults to `page`.",
+					Optional:    true,
+				},
+				"start_page": schema.Int64Attribute{
+					Description: "Number of the first page for the `page` strategy. Defaults to 1.",
+					Optional:    true,
+				},
+				"offset_param": schema.StringAttribute{
+					Description: "Query parameter of the item offset for the `offset` strategy. Defaults to `offset`.",
+					Optional:    true,
+				},
+				"page_size_param": schema.StringAttribute{
+					Description: "Query parameter the page size is sent in. Defaults to `limit` for the `offset` strategy.",
+					Optional:    true,
+				},
+				"page_size": schema.Int64Attribute{
+					Description: "Number of items requested per page. A page with fewer items is treated as the last one.",
+					Optional:    true,
+				},
+				"items_path": schema.StringAttribute{
+					Description: "Path of the items array in each page, aggregated into `items`. Required for the `page` and `offset` strategies.",
+					Optional:    true,
+				},
+				"max_pages": schema.Int64Attribute{
+					Description: "Maximum number of pages read. Reading stops with a warning when more pages are available. Defaults to 100.",
+					Optional:    true,
+				},
 			},
-			"headers": schema.MapAttribute{
-				Description: "Headers to be added.",
-				ElementType: types.StringType,
-				Optional:    true,
+		},
+		"pages": schema.ListAttribute{
+			Description: "Body of every page read with `pagination`.",
+			ElementType: types.StringType,
+			Computed:    true,
+		},
+		"items": schema.StringAttribute{
+			Description: "JSON array of the items found at `pagination.items_path` on every page.",
+			Computed:    true,
+		},
+		"wait_for": schema.SingleNestedAttribute{
+			Description: "Sends the request again until the response meets every condition set here, for APIs that are eventually consistent. Failed requests, for example while the service is not reachable yet, are sent again too. The read fails with the last response or error when `timeout_ms` passes.",
+			Optional:    true,
+			Attributes: map[string]schema.Attribute{
+				"status_codes": schema.ListAttribute{
+					Description: "Status codes to wait for, in the format of `expected_status_codes`. Defaults to `[\"2xx\"]`.",
+					ElementType: types.StringType,
+					Optional:    true,
+				},
+				"json_path": schema.StringAttribute{
+					Description: "Path that must exist in the JSON response body, in the format of `extract`.",
+					Optional:    true,
+				},
+				"json_value": schema.StringAttribute{
+					Description: "Value expected at `json_path`. Strings are compared as is, other values in their JSON form.",
+					Optional:    true,
+				},
+				"body_regex": schema.StringAttribute{
+					Description: "Regular expression the response body must match.",
+					Optional:    true,
+				},
+				"interval_ms": schema.Int64Attribute{
+					Description: "Delay between attempts in milliseconds. Defaults to 5000.",
+					Optional:    true,
+				},
+				"max_interval_ms": schema.Int64Attribute{
+					Description: "Maximum delay between attempts with the `exponential` backoff in milliseconds. Defaults to 60000.",
+					Optional:    true,
+				},
+				"backoff": schema.StringAttribute{
+					Description: "`constant` keeps `interval_ms` between attempts, `exponential` doubles it after every attempt. Defaults to `constant`.",
+					Optional:    true,
+				},
+				"timeout_ms": schema.Int64Attribute{
+					Description: "Overall time allowed for the condition to be met in milliseconds. Defaults to 300000.",
+					Optional:    true,
+				},
 			},
 		},
 	}
+	for name, attribute := range dataSourceAttributes(specs) {
+		attributes[name] = attribute
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Fetches the response for the api",
+		Attributes:  attributes,
+	}
 }
 
-func (c *curl2DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+func (c *curl2DataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
 	var config curl2DataModelRequest
 
 	diags := req.Config.Get(ctx, &config)
@@ -102,98 +206,234 @@ func (c *curl2DataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	var body io.Reader = nil
+	if config.CurlCommand.IsNull() {
+		if config.URI.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("uri"),
+				"Missing URI",
+				"Either uri and http_method or curl_command must be set",
+			)
+		}
+		if config.HTTPMethod.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("http_method"),
+				"Missing HTTP Method",
+				"Either uri and http_method or curl_command must be set",
+			)
+		}
+		return
+	}
 
-	if config.JSON.ValueString() != "" {
-		var jsonData interface{}
-		if err := json.Unmarshal([]byte(config.JSON.ValueString()), &jsonData); err != nil {
-			resp.Diagnostics.AddError(
-				"Failed to parse JSON parameter",
+	if !config.URI.IsNull() || !config.HTTPMethod.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("curl_command"),
+			"Conflicting Attributes",
+			"curl_command cannot be combined with uri or http_method",
+		)
+		return
+	}
+
+	if !config.CurlCommand.IsUnknown() {
+		if _, err := parseCurlCommand(config.CurlCommand.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("curl_command"),
+				"Invalid curl Command",
 				err.Error(),
 			)
-			return
 		}
+	}
+}
+
+func (c *curl2DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config curl2DataModelRequest
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-		requestBody, err := json.Marshal(jsonData)
+	// request holds the request settings, resolved from curl_command when it is set. The configuration
+	// saved to state is left as written.
+	request := config
+	var command *curlCommand
+	if !config.CurlCommand.IsNull() {
+		var err error
+		command, err = parseCurlCommand(config.CurlCommand.ValueString())
 		if err != nil {
-			resp.Diagnostics.AddError(
-				"Failed to marshal JSON data",
+			resp.Diagnostics.AddAttributeError(
+				path.Root("curl_command"),
+				"Invalid curl Command",
 				err.Error(),
 			)
 			return
 		}
-		body = bytes.NewBuffer(requestBody)
+
+		request.URI = types.StringValue(command.url)
+		request.HTTPMethod = types.StringValue(command.method)
+		if command.username != "" || command.password != "" {
+			if config.AuthType.ValueString() != "" {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("auth_type"),
+					"Conflicting Authentication",
+					"auth_type cannot be set when curl_command sets -u",
+				)
+				return
+			}
+			request.AuthType = types.StringValue(authTypeBasic)
+			request.BasicAuthUsername = types.StringValue(command.username)
+			request.BasicAuthPassword = types.StringValue(command.password)
+		}
 	}
 
-	newReq, err := retryablehttp.NewRequest(config.HTTPMethod.ValueString(), config.URI.ValueString(), body)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to create new http request",
-			err.Error(),
-		)
+	var overrides requestOverrides
+	overrides.insecure = command != nil && command.insecure
+	prepared, diags := prepareRequest(ctx, c.client, c.providerData, request.curl2RequestModel, overrides)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	for eachHeaderKey, eachHeaderValue := range config.Headers.Elements() {
-		newReq.Header.Set(eachHeaderKey, eachHeaderValue.String())
-	}
+	requestCtx, cancel := prepared.withTimeout(ctx)
+	defer cancel()
 
-	if config.AuthType.ValueString() != "" {
-		if config.AuthType.ValueString() == "Bearer" {
-			if config.BearerToken.ValueString() == "" {
-				resp.Diagnostics.AddError(
-					"Invalid Bearer Token",
-					"Bearer Token Parameter must be provided",
+	if command != nil {
+		if command.body != nil {
+			if prepared.body != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("curl_command"),
+					"Conflicting Request Body",
+					"The body cannot be set with json, form, multipart, raw_body or body_base64 when curl_command sends data",
 				)
 				return
 			}
-
-			newReq.Header.Set("Authorization", "Bearer "+config.BearerToken.ValueString())
+			prepared.body = command.body
 		}
 
-		if config.AuthType.ValueString() == "Basic" {
-			if config.BasicAuthUsername.ValueString() == "" || config.BasicAuthPassword.ValueString() == "" {
-				resp.Diagnostics.AddError(
-					"Invalid Basic Auth Token",
-					"Basic Username and Password Parameters must be provided",
-				)
-				return
+		for name, value := range command.headers {
+			if !hasHeader(prepared.headers, name) {
+				prepared.headers[name] = value
 			}
-
-			newReq.SetBasicAuth(config.BasicAuthUsername.ValueString(), config.BasicAuthPassword.ValueString())
 		}
 	}
 
-	r, err := (*c.client).httpClient.Do(newReq)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error calling api",
-			err.Error(),
-		)
+	pagination, diags := paginationConfigFromObject(ctx, config.Pagination)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	defer r.Body.Close()
 
-	responseData, err := io.ReadAll(r.Body)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading response body",
-			err.Error(),
-		)
+	waitFor, diags := waitForConfigFromObject(ctx, config.WaitFor)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	config.Response, diags = types.ObjectValue(
-		map[string]attr.Type{
-			"uri":         types.StringType,
-			"body":        types.StringType,
-			"status_code": types.Int64Type,
-		},
-		map[string]attr.Value{
-			"uri":         config.URI,
-			"body":        types.StringValue(string(responseData)),
-			"status_code": types.Int64Value(int64(r.StatusCode)),
-		},
-	)
+
+	uri := request.URI.ValueString()
+	var state paginationState
+	if pagination != nil {
+		var err error
+		uri, err = pagination.firstURL(uri, &state)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("uri"),
+				"Invalid URI",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	var r *http.Response
+	var responseData []byte
+	var pages []string
+	var items []json.RawMessage
+	for pageURI := uri; pageURI != ""; {
+		var pageResponse *http.Response
+		var pageData []byte
+		if r == nil && waitFor != nil {
+			pageResponse, pageData, diags = prepared.waitFor(requestCtx, waitFor, pageURI)
+		} else {
+			pageResponse, pageData, diags = prepared.send(requestCtx, pageURI)
+		}
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if detail := prepared.unexpectedStatus(pageResponse, pageData); detail != "" {
+			if config.StatusWarnOnly.ValueBool() {
+				resp.Diagnostics.AddWarning("Unexpected response status", detail)
+			} else {
+				resp.Diagnostics.AddError("Unexpected response status", detail)
+				return
+			}
+		}
+
+		// The response attribute holds the first page.
+		if r == nil {
+			r, responseData = pageResponse, pageData
+		}
+		if pagination == nil {
+			break
+		}
+
+		pageItems, err := pagination.pageItems(pageData)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("pagination").AtName("items_path"),
+				"Unable to read page items",
+				fmt.Sprintf("%s on page %d (%s)", err.Error(), len(pages)+1, redactURL(pageResponse.Request.URL)),
+			)
+			return
+		}
+		pages = append(pages, string(pageData))
+		items = append(items, pageItems...)
+
+		pageURI, err = pagination.nextURL(pageURI, pageResponse, pageData, len(pageItems), &state)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("pagination"),
+				"Unable to find the next page",
+				err.Error(),
+			)
+			return
+		}
+		if pageURI != "" && int64(len(pages)) >= pagination.maxPages {
+			resp.Diagnostics.AddWarning(
+				"Pagination stopped at max_pages",
+				fmt.Sprintf("%d pages were read and more are available. Increase pagination.max_pages to read them.", len(pages)),
+			)
+			break
+		}
+	}
+
+	config.Pages = types.ListNull(types.StringType)
+	config.Items = types.StringNull()
+	if pagination != nil {
+		config.Pages, diags = types.ListValueFrom(ctx, types.StringType, pages)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if pagination.itemsPath != "" {
+			if items == nil {
+				items = []json.RawMessage{}
+			}
+			itemsJSON, err := json.Marshal(items)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Unable to encode pagination items",
+					err.Error(),
+				)
+				return
+			}
+			config.Items = types.StringValue(string(itemsJSON))
+		}
+	}
+
+	config.Response, diags = prepared.response(ctx, r, responseData)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -210,5 +450,141 @@ func (c *curl2DataSource) Configure(ctx context.Context, req datasource.Configur
 	if req.ProviderData == nil {
 		return
 	}
-	c.client = req.ProviderData.(*HttpClient)
+	c.providerData = req.ProviderData.(*curl2ProviderData)
+	c.client = c.providerData.client
+}
+
+// extractValues resolves the configured extract paths against the response body.
+// It returns nil when nothing is configured.
+func extractValues(ctx context.Context, extract types.Map, optional types.List, body []byte) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if extract.IsNull() || extract.IsUnknown() {
+		return nil, diags
+	}
+
+	var paths map[string]string
+	diags.Append(extract.ElementsAs(ctx, &paths, false)...)
+
+	var optionalNames []string
+	if !optional.IsNull() && !optional.IsUnknown() {
+		diags.Append(optional.ElementsAs(ctx, &optionalNames, false)...)
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	isOptional := map[string]bool{}
+	for _, name := range optionalNames {
+		if _, ok := paths[name]; !ok {
+			diags.AddAttributeError(
+				path.Root("extract_optional"),
+				"Unknown Extract Name",
+				fmt.Sprintf("%q is listed in extract_optional but not defined in extract", name),
+			)
+		}
+		isOptional[name] = true
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	extracted := map[string]string{}
+	if len(paths) == 0 {
+		return extracted, diags
+	}
+
+	if !gjson.ValidBytes(body) {
+		diags.AddError(
+			"Response body is not valid JSON",
+			"Values cannot be extracted as the response body is not JSON:\n"+redactBody(body, defaultSnippetBytes),
+		)
+		return nil, diags
+	}
+
+	for name, expr := range paths {
+		result, found, err := lookupJSON(body, expr)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("extract").AtMapKey(name),
+				"Invalid Extract Path",
+				err.Error(),
+			)
+			continue
+		}
+
+		if !found {
+			if !isOptional[name] {
+				diags.AddAttributeError(
+					path.Root("extract").AtMapKey(name),
+					"Extract Path Not Found",
+					fmt.Sprintf("Path %q for %q does not exist in the response body. Add %q to extract_optional if it may be missing.", expr, name, name),
+				)
+			}
+			continue
+		}
+
+		extracted[name] = jsonResultString(result)
+	}
+
+	return extracted, diags
+}
+
+// dataSourceAttributes converts attribute specs into data source schema attributes.
+func dataSourceAttributes(specs map[string]attributeSpec) map[string]schema.Attribute {
+	attributes := map[string]schema.Attribute{}
+	for name, spec := range specs {
+		switch spec.kind {
+		case stringAttribute:
+			attributes[name] = schema.StringAttribute{
+				Description: spec.description,
+				Required:    spec.required,
+				Optional:    !spec.required,
+				Sensitive:   spec.sensitive,
+			}
+		case int64Attribute:
+			attributes[name] = schema.Int64Attribute{
+				Description: spec.description,
+				Required:    spec.required,
+				Optional:    !spec.required,
+				Sensitive:   spec.sensitive,
+			}
+		case stringListAttribute:
+			attributes[name] = schema.ListAttribute{
+				Description: spec.description,
+				ElementType: types.StringType,
+				Required:    spec.required,
+				Optional:    !spec.required,
+				Sensitive:   spec.sensitive,
+			}
+		case stringMapAttribute:
+			attributes[name] = schema.MapAttribute{
+				Description: spec.description,
+				ElementType: types.StringType,
+				Required:    spec.required,
+				Optional:    !spec.required,
+				Sensitive:   spec.sensitive,
+			}
+		case nestedAttribute:
+			attributes[name] = schema.SingleNestedAttribute{
+				Description: spec.description,
+				Required:    spec.required,
+				Optional:    !spec.required,
+				Sensitive:   spec.sensitive,
+				Attributes:  dataSourceAttributes(spec.attributes),
+			}
+		}
+	}
+	return attributes
+}
+
+func tlsDataSourceAttributes() map[string]schema.Attribute {
+	return dataSourceAttributes(tlsAttributeSpecs())
+}
+
+func awsSigV4DataSourceAttributes() map[string]schema.Attribute {
+	return dataSourceAttributes(awsSigV4AttributeSpecs())
+}
+
+func hmacDataSourceAttributes() map[string]schema.Attribute {
+	return dataSourceAttributes(hmacAttributeSpecs())
 }
@@ -2,14 +2,12 @@ package curl2
 
 import (
 	"context"
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"os"
 )
 
 var (
@@ -26,12 +24,26 @@ type azureADTokenDataModelRequest struct {
 	Response types.Object `tfsdk:"response"`
 }
 
-type azureADTokenDataSource struct{}
+type azureADTokenDataSource struct {
+	providerData *curl2ProviderData
+}
+
+func azureADTokenResponseAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"token":           types.StringType,
+		"expires_on":      types.StringType,
+		"credential_type": types.StringType,
+		"client_id":       types.StringType,
+		"tenant_id":       types.StringType,
+		"object_id":       types.StringType,
+	}
+}
 
 func (a *azureADTokenDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
+	a.providerData = req.ProviderData.(*curl2ProviderData)
 }
 
 func (a *azureADTokenDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -43,79 +55,7 @@ func (a *azureADTokenDataSource) Read(ctx context.Context, req datasource.ReadRe
 		return
 	}
 
-	clientID := os.Getenv("AZURE_CLIENT_ID")
-	clientSecret := os.Getenv("AZURE_CLIENT_SECRET")
-	tenantID := os.Getenv("AZURE_TENANT_ID")
-
-	if clientID == "" {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("client_id"),
-			"Missing Client ID",
-			"The data source cannot get the token as client id is missing in provider azure_ad block",
-		)
-	}
-
-	if clientSecret == "" {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("client_secret"),
-			"Missing Client Secret",
-			"The data source cannot get the token as client secret is missing in provider azure_ad block",
-		)
-	}
-
-	if tenantID == "" {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("tenant_id"),
-			"Missing Tenant ID",
-			"The data source cannot get the token as tenant id is missing in provider azure_ad block",
-		)
-	}
-
-	if len(config.Scopes.Elements()) == 0 {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("scopes"),
-			"Missing Scopes",
-			"The data source cannot get the token as scopes are missing",
-		)
-	}
-
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	cred, err := azidentity.NewDefaultAzureCredential(nil)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error getting default azure credentials",
-			err.Error(),
-		)
-		return
-	}
-	var scopeArr []string
-	config.Scopes.ElementsAs(ctx, &scopeArr, false)
-
-	token, err := cred.GetToken(context.Background(), policy.TokenRequestOptions{
-		TenantID: tenantID,
-		Scopes:   scopeArr,
-	})
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error getting azure ad token",
-			err.Error(),
-		)
-		return
-	}
-
-	config.Response, diags = types.ObjectValue(
-		map[string]attr.Type{
-			"token":      types.StringType,
-			"expires_on": types.StringType,
-		},
-		map[string]attr.Value{
-			"token":      types.StringValue(token.Token),
-			"expires_on": types.StringValue(token.ExpiresOn.String()),
-		},
-	)
+	config.Response, diags = azureADTokenResponse(ctx, a.providerData, config.Scopes)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -126,7 +66,6 @@ func (a *azureADTokenDataSource) Read(ctx context.Context, req datasource.ReadRe
 	if resp.Diagnostics.HasError() {
 		return
 	}
-
 }
 
 func (a *azureADTokenDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -143,13 +82,67 @@ func (a *azureADTokenDataSource) Schema(ctx context.Context, req datasource.Sche
 				Required:    true,
 			},
 			"response": schema.ObjectAttribute{
-				AttributeTypes: map[string]attr.Type{
-					"token":      types.StringType,
-					"expires_on": types.StringType,
-				},
-				Description: "Token response.",
-				Computed:    true,
+				AttributeTypes: azureADTokenResponseAttrTypes(),
+				Description:    "Token response, along with the credential type and the client, tenant and object id of the identity the token was issued to.",
+				Computed:       true,
+				Sensitive:      true,
 			},
 		},
 	}
 }
+
+// azureADTokenResponse fetches a token for the scopes with the provider azure_ad configuration and
+// builds the `response` object shared by the data source and the ephemeral resource.
+func azureADTokenResponse(ctx context.Context, providerData *curl2ProviderData, scopes types.List) (types.Object, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	response := types.ObjectNull(azureADTokenResponseAttrTypes())
+
+	if err := providerData.azureAD.validate(); err != nil {
+		diags.AddError(
+			"Invalid Azure AD Configuration",
+			"The token cannot be fetched as "+err.Error(),
+		)
+	}
+
+	if len(scopes.Elements()) == 0 {
+		diags.AddAttributeError(
+			path.Root("scopes"),
+			"Missing Scopes",
+			"The token cannot be fetched as scopes are missing",
+		)
+	}
+
+	if diags.HasError() {
+		return response, diags
+	}
+
+	var scopeArr []string
+	diags.Append(scopes.ElementsAs(ctx, &scopeArr, false)...)
+	if diags.HasError() {
+		return response, diags
+	}
+
+	token, err := providerData.azureADToken(ctx, scopeArr)
+	if err != nil {
+		diags.AddError(
+			"Error getting azure ad token",
+			err.Error(),
+		)
+		return response, diags
+	}
+	identity := providerData.azureAD.identity(token.Token)
+
+	response, objectDiags := types.ObjectValue(
+		azureADTokenResponseAttrTypes(),
+		map[string]attr.Value{
+			"token":           types.StringValue(token.Token),
+			"expires_on":      types.StringValue(token.ExpiresOn.String()),
+			"credential_type": types.StringValue(identity.CredentialType),
+			"client_id":       types.StringValue(identity.ClientID),
+			"tenant_id":       types.StringValue(identity.TenantID),
+			"object_id":       types.StringValue(identity.ObjectID),
+		},
+	)
+	diags.Append(objectDiags...)
+	return response, diags
+}
@@ -0,0 +1,374 @@
+package curl2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
+)
+
+var (
+	_ datasource.DataSource              = &curl2GraphQLDataSource{}
+	_ datasource.DataSourceWithConfigure = &curl2GraphQLDataSource{}
+)
+
+func NewCurl2GraphQLDataSource() datasource.DataSource {
+	return &curl2GraphQLDataSource{}
+}
+
+type curl2GraphQLDataModel struct {
+	Endpoint          types.String  `tfsdk:"endpoint"`
+	Query             types.String  `tfsdk:"query"`
+	Variables         types.Dynamic `tfsdk:"variables"`
+	OperationName     types.String  `tfsdk:"operation_name"`
+	Headers           types.Map     `tfsdk:"headers"`
+	AuthType          types.String  `tfsdk:"auth_type"`
+	BearerToken       types.String  `tfsdk:"bearer_token"`
+	BasicAuthUsername types.String  `tfsdk:"basic_auth_username"`
+	BasicAuthPassword types.String  `tfsdk:"basic_auth_password"`
+	TokenScopes       types.List    `tfsdk:"token_scopes"`
+	TokenAudience     types.String  `tfsdk:"token_audience"`
+	AWSSigV4          types.Object  `tfsdk:"aws_sigv4"`
+	HMAC              types.Object  `tfsdk:"hmac"`
+	Data              types.String  `tfsdk:"data"`
+	Response          types.Object  `tfsdk:"response"`
+}
+
+// graphQLRequest is the JSON body of a GraphQL request.
+type graphQLRequest struct {
+	Query         string      `json:"query"`
+	Variables     interface{} `json:"variables,omitempty"`
+	OperationName string      `json:"operationName,omitempty"`
+}
+
+// graphQLResponse is the JSON body of a GraphQL response.
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphQLError  `json:"errors"`
+}
+
+type graphQLError struct {
+	Message   string        `json:"message"`
+	Path      []interface{} `json:"path"`
+	Locations []struct {
+		Line   int `json:"line"`
+		Column int `json:"column"`
+	} `json:"locations"`
+}
+
+type curl2GraphQLDataSource struct {
+	client       *HttpClient
+	providerData *curl2ProviderData
+}
+
+func (c *curl2GraphQLDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_graphql"
+}
+
+func (c *curl2GraphQLDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Sends a GraphQL query and returns its data",
+		Attributes: map[string]schema.Attribute{
+			"endpoint": schema.StringAttribute{
+				Description: "URL of the GraphQL endpoint.",
+				Required:    true,
+			},
+			"query": schema.StringAttribute{
+				Description: "GraphQL query or mutation document.",
+				Required:    true,
+			},
+			"variables": schema.DynamicAttribute{
+				Description: "Variables of the query, as an object.",
+				Optional:    true,
+			},
+			"operation_name": schema.StringAttribute{
+				Description: "Name of the operation to run when the query contains several.",
+				Optional:    true,
+			},
+			"headers": schema.MapAttribute{
+				Description: "Headers to be added.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"auth_type": schema.StringAttribute{
+				Description: "Authentication Type, Bearer, Basic, AzureAD, Auth0, OAuth2, AWSv4 or HMAC. AzureAD, Auth0 and OAuth2 fetch a token with the matching provider block and send it as a Bearer token. AWSv4 signs the request with `aws_sigv4` and HMAC with `hmac`.",
+				Optional:    true,
+			},
+			"bearer_token": schema.StringAttribute{
+				Description: "Bearer Token to be used for Authentication.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"basic_auth_username": schema.StringAttribute{
+				Description: "Username to be used for Basic Authentication.",
+				Optional:    true,
+			},
+			"basic_auth_password": schema.StringAttribute{
+				Description: "Password to be used for Authentication.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"token_scopes": schema.ListAttribute{
+				Description: "Scopes of the token fetched for the AzureAD and OAuth2 auth types. Example: [\"https://graph.microsoft.com/.default\"]",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"token_audience": schema.StringAttribute{
+				Description: "Audience of the token fetched for the Auth0 and OAuth2 auth types.",
+				Optional:    true,
+			},
+			"aws_sigv4": schema.SingleNestedAttribute{
+				Description: "AWS Signature Version 4 settings for the AWSv4 auth type.",
+				Optional:    true,
+				Attributes:  awsSigV4DataSourceAttributes(),
+			},
+			"hmac": schema.SingleNestedAttribute{
+				Description: "HMAC signature settings for the HMAC auth type.",
+				Optional:    true,
+				Attributes:  hmacDataSourceAttributes(),
+			},
+			"data": schema.StringAttribute{
+				Description: "`data` of the GraphQL response in JSON format.",
+				Computed:    true,
+			},
+			"response": schema.ObjectAttribute{
+				AttributeTypes: responseAttrTypes(),
+				Description:    "Valued returned by the HTTP request.",
+				Computed:       true,
+			},
+		},
+	}
+}
+
+func (c *curl2GraphQLDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c.providerData = req.ProviderData.(*curl2ProviderData)
+	c.client = c.providerData.client
+}
+
+func (c *curl2GraphQLDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config curl2GraphQLDataModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	request := graphQLRequest{
+		Query:         config.Query.ValueString(),
+		OperationName: config.OperationName.ValueString(),
+	}
+	if !config.Variables.IsNull() {
+		variables, err := jsonFromValue(config.Variables)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("variables"),
+				"Invalid Variables",
+				err.Error(),
+			)
+			return
+		}
+		if _, ok := variables.(map[string]interface{}); !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("variables"),
+				"Invalid Variables",
+				"Variables must be an object",
+			)
+			return
+		}
+		request.Variables = variables
+	}
+
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to encode GraphQL request",
+			err.Error(),
+		)
+		return
+	}
+
+	headers, diags := headersFromMap(ctx, config.Headers)
+	resp.Diagnostics.Append(diags...)
+
+	var tokenScopes []string
+	if !config.TokenScopes.IsNull() && !config.TokenScopes.IsUnknown() {
+		resp.Diagnostics.Append(config.TokenScopes.ElementsAs(ctx, &tokenScopes, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	newReq, err := retryablehttp.NewRequestWithContext(ctx, "POST", config.Endpoint.ValueString(), requestBody)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create new http request",
+			err.Error(),
+		)
+		return
+	}
+	newReq.Header.Set("Content-Type", "application/json")
+	newReq.Header.Set("Accept", "application/graphql-response+json, application/json")
+	setHeaders(newReq, headers)
+
+	resp.Diagnostics.Append(applyAuth(ctx, newReq, authConfig{
+		AuthType:          config.AuthType.ValueString(),
+		BearerToken:       config.BearerToken.ValueString(),
+		BasicAuthUsername: config.BasicAuthUsername.ValueString(),
+		BasicAuthPassword: config.BasicAuthPassword.ValueString(),
+		TokenScopes:       tokenScopes,
+		TokenAudience:     config.TokenAudience.ValueString(),
+		AWSSigV4:          config.AWSSigV4,
+		HMAC:              config.HMAC,
+	}, c.providerData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r, responseData, err := c.client.Do(newReq)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error calling api",
+			err.Error(),
+		)
+		return
+	}
+
+	// GraphQL servers report errors in the body, usually with a 200 status, so they are read before the status.
+	var response graphQLResponse
+	if err = json.Unmarshal(responseData, &response); err != nil {
+		if r.StatusCode < 200 || r.StatusCode > 299 {
+			resp.Diagnostics.AddError("Unexpected response status", unexpectedStatusDetail(r, responseData, nil))
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Invalid GraphQL response",
+			"The response body is not a GraphQL response:\n"+redactBody(responseData, defaultSnippetBytes),
+		)
+		return
+	}
+
+	for _, graphQLErr := range response.Errors {
+		resp.Diagnostics.AddError("GraphQL error", graphQLErr.detail())
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		resp.Diagnostics.AddError("Unexpected response status", unexpectedStatusDetail(r, responseData, nil))
+		return
+	}
+
+	data := bytes.TrimSpace(response.Data)
+	if len(data) == 0 {
+		data = []byte("null")
+	}
+	config.Data = types.StringValue(string(data))
+
+	config.Response, diags = responseValue(config.Endpoint.ValueString(), r, responseData, nil)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// detail describes the error along with the path of the field and the locations in the query it relates to.
+func (e graphQLError) detail() string {
+	detail := e.Message
+
+	if len(e.Path) > 0 {
+		segments := make([]string, len(e.Path))
+		for i, segment := range e.Path {
+			segments[i] = fmt.Sprint(segment)
+		}
+		detail += "\nPath: " + strings.Join(segments, ".")
+	}
+
+	if len(e.Locations) > 0 {
+		locations := make([]string, len(e.Locations))
+		for i, location := range e.Locations {
+			locations[i] = fmt.Sprintf("line %d, column %d", location.Line, location.Column)
+		}
+		detail += "\nLocations: " + strings.Join(locations, "; ")
+	}
+
+	return detail
+}
+
+// jsonFromValue converts a Terraform value into the matching JSON value. Unknown values are an error.
+func jsonFromValue(value attr.Value) (interface{}, error) {
+	if value.IsUnknown() {
+		return nil, fmt.Errorf("value is not known yet")
+	}
+	if value.IsNull() {
+		return nil, nil
+	}
+
+	switch v := value.(type) {
+	case types.Dynamic:
+		return jsonFromValue(v.UnderlyingValue())
+	case types.String:
+		return v.ValueString(), nil
+	case types.Bool:
+		return v.ValueBool(), nil
+	case types.Number:
+		return json.Number(v.ValueBigFloat().Text('g', -1)), nil
+	case types.Int64:
+		return v.ValueInt64(), nil
+	case types.Float64:
+		return v.ValueFloat64(), nil
+	case types.List:
+		return jsonFromElements(v.Elements())
+	case types.Set:
+		return jsonFromElements(v.Elements())
+	case types.Tuple:
+		return jsonFromElements(v.Elements())
+	case types.Map:
+		return jsonFromAttributes(v.Elements())
+	case types.Object:
+		return jsonFromAttributes(v.Attributes())
+	default:
+		return nil, fmt.Errorf("unsupported value of type %s", value.Type(context.Background()))
+	}
+}
+
+func jsonFromElements(elements []attr.Value) (interface{}, error) {
+	result := make([]interface{}, len(elements))
+	for i, element := range elements {
+		elementJSON, err := jsonFromValue(element)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = elementJSON
+	}
+	return result, nil
+}
+
+func jsonFromAttributes(attributes map[string]attr.Value) (interface{}, error) {
+	result := map[string]interface{}{}
+	for name, attribute := range attributes {
+		attributeJSON, err := jsonFromValue(attribute)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = attributeJSON
+	}
+	return result, nil
+}
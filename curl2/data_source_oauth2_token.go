@@ -0,0 +1,191 @@
+package curl2
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &oauth2TokenDataSource{}
+	_ datasource.DataSourceWithConfigure = &oauth2TokenDataSource{}
+)
+
+func NewOAuth2TokenDataSource() datasource.DataSource {
+	return &oauth2TokenDataSource{}
+}
+
+type oauth2TokenDataModelRequest struct {
+	TokenURL        types.String `tfsdk:"token_url"`
+	ClientID        types.String `tfsdk:"client_id"`
+	ClientSecret    types.String `tfsdk:"client_secret"`
+	ClientAuthStyle types.String `tfsdk:"client_auth_style"`
+	GrantType       types.String `tfsdk:"grant_type"`
+	Scopes          types.List   `tfsdk:"scopes"`
+	Audience        types.String `tfsdk:"audience"`
+	Username        types.String `tfsdk:"username"`
+	Password        types.String `tfsdk:"password"`
+	RefreshToken    types.String `tfsdk:"refresh_token"`
+	ExtraParams     types.Map    `tfsdk:"extra_params"`
+	Response        types.Object `tfsdk:"response"`
+}
+
+type oauth2TokenDataSource struct {
+	providerData *curl2ProviderData
+}
+
+func oauth2TokenResponseAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"access_token":  types.StringType,
+		"token_type":    types.StringType,
+		"expires_in":    types.Int64Type,
+		"expires_at":    types.StringType,
+		"scope":         types.StringType,
+		"refresh_token": types.StringType,
+	}
+}
+
+func (o *oauth2TokenDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	o.providerData = req.ProviderData.(*curl2ProviderData)
+}
+
+func (o *oauth2TokenDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_oauth2_token"
+}
+
+func (o *oauth2TokenDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches a token from any OAuth2 token endpoint, such as Keycloak or Okta",
+		Attributes: map[string]schema.Attribute{
+			"token_url": schema.StringAttribute{
+				Description: "Token endpoint URL. Example: \"https://idp.example.com/realms/main/protocol/openid-connect/token\"",
+				Required:    true,
+			},
+			"client_id": schema.StringAttribute{
+				Description: "Client ID of the application.",
+				Required:    true,
+			},
+			"client_secret": schema.StringAttribute{
+				Description: "Client secret of the application.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"client_auth_style": schema.StringAttribute{
+				Description: "How the client credentials are sent, `basic` for an HTTP Basic Authorization header or `body` for form parameters. Defaults to `basic`.",
+				Optional:    true,
+			},
+			"grant_type": schema.StringAttribute{
+				Description: "Grant type, one of `client_credentials`, `password` or `refresh_token`. Defaults to `client_credentials`.",
+				Optional:    true,
+			},
+			"scopes": schema.ListAttribute{
+				Description: "Scopes to request.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"audience": schema.StringAttribute{
+				Description: "Audience to request, for IdPs that support it.",
+				Optional:    true,
+			},
+			"username": schema.StringAttribute{
+				Description: "Resource owner username for the `password` grant.",
+				Optional:    true,
+			},
+			"password": schema.StringAttribute{
+				Description: "Resource owner password for the `password` grant.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"refresh_token": schema.StringAttribute{
+				Description: "Refresh token for the `refresh_token` grant.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"extra_params": schema.MapAttribute{
+				Description: "Additional form parameters sent to the token endpoint.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"response": schema.ObjectAttribute{
+				AttributeTypes: oauth2TokenResponseAttrTypes(),
+				Description:    "Token response. `expires_at` is in RFC 3339 format.",
+				Computed:       true,
+				Sensitive:      true,
+			},
+		},
+	}
+}
+
+func (o *oauth2TokenDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config oauth2TokenDataModelRequest
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tokenRequest := oauth2TokenRequest{
+		TokenURL:        config.TokenURL.ValueString(),
+		ClientID:        config.ClientID.ValueString(),
+		ClientSecret:    config.ClientSecret.ValueString(),
+		ClientAuthStyle: clientAuthStyleBasic,
+		GrantType:       grantTypeClientCredentials,
+		Audience:        config.Audience.ValueString(),
+		Username:        config.Username.ValueString(),
+		Password:        config.Password.ValueString(),
+		RefreshToken:    config.RefreshToken.ValueString(),
+	}
+	if config.ClientAuthStyle.ValueString() != "" {
+		tokenRequest.ClientAuthStyle = config.ClientAuthStyle.ValueString()
+	}
+	if config.GrantType.ValueString() != "" {
+		tokenRequest.GrantType = config.GrantType.ValueString()
+	}
+
+	if !config.Scopes.IsNull() {
+		resp.Diagnostics.Append(config.Scopes.ElementsAs(ctx, &tokenRequest.Scopes, false)...)
+	}
+	if !config.ExtraParams.IsNull() {
+		resp.Diagnostics.Append(config.ExtraParams.ElementsAs(ctx, &tokenRequest.ExtraParams, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	token, err := o.providerData.oauth2Token(ctx, tokenRequest)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting oauth2 token",
+			err.Error(),
+		)
+		return
+	}
+
+	config.Response, diags = types.ObjectValue(
+		oauth2TokenResponseAttrTypes(),
+		map[string]attr.Value{
+			"access_token":  types.StringValue(token.AccessToken),
+			"token_type":    types.StringValue(token.TokenType),
+			"expires_in":    types.Int64Value(token.expiresInSeconds()),
+			"expires_at":    types.StringValue(token.expiresAtString()),
+			"scope":         types.StringValue(token.Scope),
+			"refresh_token": types.StringValue(token.RefreshToken),
+		},
+	)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
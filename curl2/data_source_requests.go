@@ -0,0 +1,447 @@
+package curl2
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const defaultRequestsConcurrency = 10
+
+var (
+	_ datasource.DataSource              = &curl2RequestsDataSource{}
+	_ datasource.DataSourceWithConfigure = &curl2RequestsDataSource{}
+)
+
+func NewCurl2RequestsDataSource() datasource.DataSource {
+	return &curl2RequestsDataSource{}
+}
+
+type curl2RequestsDataModel struct {
+	Requests          types.Map    `tfsdk:"requests"`
+	Concurrency       types.Int64  `tfsdk:"concurrency"`
+	FailFast          types.Bool   `tfsdk:"fail_fast"`
+	AuthType          types.String `tfsdk:"auth_type"`
+	BearerToken       types.String `tfsdk:"bearer_token"`
+	BasicAuthUsername types.String `tfsdk:"basic_auth_username"`
+	BasicAuthPassword types.String `tfsdk:"basic_auth_password"`
+	TokenScopes       types.List   `tfsdk:"token_scopes"`
+	TokenAudience     types.String `tfsdk:"token_audience"`
+	AWSSigV4          types.Object `tfsdk:"aws_sigv4"`
+	HMAC              types.Object `tfsdk:"hmac"`
+	Responses         types.Map    `tfsdk:"responses"`
+}
+
+type requestSpecModel struct {
+	HTTPMethod     types.String `tfsdk:"http_method"`
+	URI            types.String `tfsdk:"uri"`
+	Body           types.String `tfsdk:"body"`
+	Headers        types.Map    `tfsdk:"headers"`
+	ExpectedStatus types.List   `tfsdk:"expected_status_codes"`
+}
+
+// requestSpec is a request of the batch, resolved from its requestSpecModel.
+type requestSpec struct {
+	method         string
+	uri            string
+	body           string
+	headers        map[string]string
+	expectedStatus []string
+}
+
+// requestResult is the outcome of one request of the batch. diags holds the reason the request failed.
+type requestResult struct {
+	response *http.Response
+	body     []byte
+	diags    diag.Diagnostics
+}
+
+type curl2RequestsDataSource struct {
+	client       *HttpClient
+	providerData *curl2ProviderData
+}
+
+// requestsResponseAttrTypes describes each entry of `responses`, the `response` object along with the error of the request.
+func requestsResponseAttrTypes() map[string]attr.Type {
+	attrTypes := responseAttrTypes()
+	attrTypes["error"] = types.StringType
+	return attrTypes
+}
+
+func (c *curl2RequestsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_requests"
+}
+
+func (c *curl2RequestsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Sends many requests concurrently and returns their responses by name",
+		Attributes: map[string]schema.Attribute{
+			"requests": schema.MapNestedAttribute{
+				Description: "Requests to send, by name.",
+				Required:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"http_method": schema.StringAttribute{
+							Description: "HTTP method like GET, POST, PUT, DELETE, PATCH. Defaults to GET.",
+							Optional:    true,
+						},
+						"uri": schema.StringAttribute{
+							Description: "URI of the request.",
+							Required:    true,
+						},
+						"body": schema.StringAttribute{
+							Description: "Request body sent as is.",
+							Optional:    true,
+						},
+						"headers": schema.MapAttribute{
+							Description: "Headers to be added.",
+							ElementType: types.StringType,
+							Optional:    true,
+						},
+						"expected_status_codes": schema.ListAttribute{
+							Description: "Status codes the response must match, given as exact codes like `201`, classes like `2xx` or ranges like `200-299`. By default any status is accepted.",
+							ElementType: types.StringType,
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"concurrency": schema.Int64Attribute{
+				Description: "Maximum number of requests in flight at the same time. Defaults to 10.",
+				Optional:    true,
+			},
+			"fail_fast": schema.BoolAttribute{
+				Description: "Fail the read on the first request that cannot be sent or returns an unexpected status, cancelling the requests still running. When false, every request is sent, failures are reported in the `error` of their response and the read only warns about them. Defaults to true.",
+				Optional:    true,
+			},
+			"auth_type": schema.StringAttribute{
+				Description: "Authentication Type, Bearer, Basic, AzureAD, Auth0, OAuth2, AWSv4 or HMAC. AzureAD, Auth0 and OAuth2 fetch a token with the matching provider block and send it as a Bearer token. AWSv4 signs the request with `aws_sigv4` and HMAC with `hmac`. Applies to every request.",
+				Optional:    true,
+			},
+			"bearer_token": schema.StringAttribute{
+				Description: "Bearer Token to be used for Authentication.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"basic_auth_username": schema.StringAttribute{
+				Description: "Username to be used for Basic Authentication.",
+				Optional:    true,
+			},
+			"basic_auth_password": schema.StringAttribute{
+				Description: "Password to be used for Authentication.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"token_scopes": schema.ListAttribute{
+				Description: "Scopes of the token fetched for the AzureAD and OAuth2 auth types. Example: [\"https://graph.microsoft.com/.default\"]",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"token_audience": schema.StringAttribute{
+				Description: "Audience of the token fetched for the Auth0 and OAuth2 auth types.",
+				Optional:    true,
+			},
+			"aws_sigv4": schema.SingleNestedAttribute{
+				Description: "AWS Signature Version 4 settings for the AWSv4 auth type.",
+				Optional:    true,
+				Attributes:  awsSigV4DataSourceAttributes(),
+			},
+			"hmac": schema.SingleNestedAttribute{
+				Description: "HMAC signature settings for the HMAC auth type.",
+				Optional:    true,
+				Attributes:  hmacDataSourceAttributes(),
+			},
+			"responses": schema.MapAttribute{
+				Description: "Value returned by each request, by name, along with `error` describing why the request failed. Only the fields known at the time of the failure are set on a failed request.",
+				ElementType: types.ObjectType{AttrTypes: requestsResponseAttrTypes()},
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (c *curl2RequestsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c.providerData = req.ProviderData.(*curl2ProviderData)
+	c.client = c.providerData.client
+}
+
+func (c *curl2RequestsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config curl2RequestsDataModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	concurrency := int64(defaultRequestsConcurrency)
+	if !config.Concurrency.IsNull() && !config.Concurrency.IsUnknown() {
+		concurrency = config.Concurrency.ValueInt64()
+	}
+	if concurrency < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("concurrency"),
+			"Invalid Concurrency",
+			"Concurrency must be at least 1",
+		)
+		return
+	}
+
+	failFast := true
+	if !config.FailFast.IsNull() && !config.FailFast.IsUnknown() {
+		failFast = config.FailFast.ValueBool()
+	}
+
+	var specModels map[string]requestSpecModel
+	diags = config.Requests.ElementsAs(ctx, &specModels, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	specs := map[string]requestSpec{}
+	for name, model := range specModels {
+		spec := requestSpec{
+			method: model.HTTPMethod.ValueString(),
+			uri:    model.URI.ValueString(),
+			body:   model.Body.ValueString(),
+		}
+		if spec.method == "" {
+			spec.method = http.MethodGet
+		}
+
+		spec.headers, diags = headersFromMap(ctx, model.Headers)
+		resp.Diagnostics.Append(diags...)
+
+		if !model.ExpectedStatus.IsNull() && !model.ExpectedStatus.IsUnknown() {
+			resp.Diagnostics.Append(model.ExpectedStatus.ElementsAs(ctx, &spec.expectedStatus, false)...)
+		}
+		for _, pattern := range spec.expectedStatus {
+			if _, _, err := parseStatusPattern(pattern); err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("requests").AtMapKey(name).AtName("expected_status_codes"),
+					"Invalid Expected Status Code",
+					err.Error(),
+				)
+			}
+		}
+		specs[name] = spec
+	}
+
+	var tokenScopes []string
+	if !config.TokenScopes.IsNull() && !config.TokenScopes.IsUnknown() {
+		resp.Diagnostics.Append(config.TokenScopes.ElementsAs(ctx, &tokenScopes, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	auth := authConfig{
+		AuthType:          config.AuthType.ValueString(),
+		BearerToken:       config.BearerToken.ValueString(),
+		BasicAuthUsername: config.BasicAuthUsername.ValueString(),
+		BasicAuthPassword: config.BasicAuthPassword.ValueString(),
+		TokenScopes:       tokenScopes,
+		TokenAudience:     config.TokenAudience.ValueString(),
+		AWSSigV4:          config.AWSSigV4,
+		HMAC:              config.HMAC,
+	}
+
+	results, firstFailure := c.sendAll(ctx, specs, auth, int(concurrency), failFast)
+
+	if failFast && firstFailure != "" {
+		for _, d := range results[firstFailure].diags.Errors() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("requests").AtMapKey(firstFailure),
+				d.Summary(),
+				fmt.Sprintf("Request %q failed: %s", firstFailure, d.Detail()),
+			)
+		}
+		return
+	}
+
+	responses := map[string]attr.Value{}
+	var failed []string
+	for name, result := range results {
+		errorValue := types.StringNull()
+		if result.diags.HasError() {
+			failed = append(failed, name)
+			var messages []string
+			for _, d := range result.diags.Errors() {
+				messages = append(messages, d.Summary()+": "+d.Detail())
+			}
+			errorValue = types.StringValue(strings.Join(messages, "\n"))
+		}
+
+		responses[name], diags = requestsResponseValue(specs[name].uri, result, errorValue)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		resp.Diagnostics.AddWarning(
+			"Some requests failed",
+			fmt.Sprintf("%d of %d requests failed: %s. See the error of their responses for details.", len(failed), len(results), strings.Join(failed, ", ")),
+		)
+	}
+
+	config.Responses, diags = types.MapValue(types.ObjectType{AttrTypes: requestsResponseAttrTypes()}, responses)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// sendAll sends every request with at most concurrency of them in flight. With failFast, the first
+// failure cancels the requests that are still running or waiting. It returns the result of every request
+// along with the name of the first one that failed, if any.
+func (c *curl2RequestsDataSource) sendAll(ctx context.Context, specs map[string]requestSpec, auth authConfig, concurrency int, failFast bool) (map[string]*requestResult, string) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := map[string]*requestResult{}
+	firstFailure := ""
+	slots := make(chan struct{}, concurrency)
+
+	for name, spec := range specs {
+		wg.Add(1)
+		go func(name string, spec requestSpec) {
+			defer wg.Done()
+
+			result := &requestResult{}
+			select {
+			case slots <- struct{}{}:
+				result.response, result.body, result.diags = c.send(ctx, spec, auth)
+				<-slots
+			case <-ctx.Done():
+				result.diags.AddError("Request not sent", ctx.Err().Error())
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[name] = result
+			if result.diags.HasError() && firstFailure == "" && ctx.Err() == nil {
+				firstFailure = name
+				if failFast {
+					cancel()
+				}
+			}
+		}(name, spec)
+	}
+	wg.Wait()
+
+	return results, firstFailure
+}
+
+// send issues one request of the batch. An unexpected status is reported as an error along with the response.
+func (c *curl2RequestsDataSource) send(ctx context.Context, spec requestSpec, auth authConfig) (*http.Response, []byte, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var body interface{}
+	if spec.body != "" {
+		body = []byte(spec.body)
+	}
+
+	newReq, err := retryablehttp.NewRequestWithContext(ctx, spec.method, spec.uri, body)
+	if err != nil {
+		diags.AddError(
+			"Unable to create new http request",
+			err.Error(),
+		)
+		return nil, nil, diags
+	}
+	setHeaders(newReq, spec.headers)
+
+	diags.Append(applyAuth(ctx, newReq, auth, c.providerData)...)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+
+	r, responseData, err := c.client.Do(newReq)
+	if err != nil {
+		diags.AddError(
+			"Error calling api",
+			err.Error(),
+		)
+		return nil, nil, diags
+	}
+
+	if len(spec.expectedStatus) > 0 {
+		if matched, _ := statusMatches(r.StatusCode, spec.expectedStatus); !matched {
+			diags.AddError(
+				"Unexpected response status",
+				unexpectedStatusDetail(r, responseData, spec.expectedStatus),
+			)
+		}
+	}
+
+	return r, responseData, diags
+}
+
+// requestsResponseValue builds an entry of `responses`. Without a response, only `uri` and `error` are set.
+func requestsResponseValue(uri string, result *requestResult, errorValue types.String) (attr.Value, diag.Diagnostics) {
+	attrTypes := requestsResponseAttrTypes()
+	attributes := map[string]attr.Value{}
+
+	if result.response != nil {
+		response, diags := responseValue(uri, result.response, result.body, nil)
+		if diags.HasError() {
+			return types.ObjectNull(attrTypes), diags
+		}
+		for name, value := range response.Attributes() {
+			attributes[name] = value
+		}
+	} else {
+		for name, attrType := range responseAttrTypes() {
+			attributes[name] = nullValue(attrType)
+		}
+		attributes["uri"] = types.StringValue(uri)
+	}
+	attributes["error"] = errorValue
+
+	return types.ObjectValue(attrTypes, attributes)
+}
+
+// nullValue returns the null value of the attribute types used by the `response` object.
+func nullValue(attrType attr.Type) attr.Value {
+	switch t := attrType.(type) {
+	case types.ListType:
+		return types.ListNull(t.ElemType)
+	case types.MapType:
+		return types.MapNull(t.ElemType)
+	case types.ObjectType:
+		return types.ObjectNull(t.AttrTypes)
+	}
+
+	switch attrType {
+	case types.Int64Type:
+		return types.Int64Null()
+	case types.BoolType:
+		return types.BoolNull()
+	}
+	return types.StringNull()
+}
@@ -0,0 +1,155 @@
+package curl2
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &curl2EphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &curl2EphemeralResource{}
+)
+
+func NewCurl2EphemeralResource() ephemeral.EphemeralResource {
+	return &curl2EphemeralResource{}
+}
+
+type curl2EphemeralModel struct {
+	curl2RequestModel
+}
+
+type curl2EphemeralResource struct {
+	client       *HttpClient
+	providerData *curl2ProviderData
+}
+
+func (c *curl2EphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName
+}
+
+func (c *curl2EphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	attributes := map[string]schema.Attribute{
+		"uri": schema.StringAttribute{
+			Description: "URI of resource you'd like to retrieve via HTTP(s).",
+			Required:    true,
+		},
+		"http_method": schema.StringAttribute{
+			Description: "HTTP method like GET, POST, PUT, DELETE, PATCH.",
+			Required:    true,
+		},
+		"response": schema.ObjectAttribute{
+			AttributeTypes: responseAttrTypes(),
+			Description:    "Valued returned by the HTTP request.",
+			Computed:       true,
+			Sensitive:      true,
+		},
+	}
+	for name, attribute := range ephemeralAttributes(requestAttributeSpecs()) {
+		attributes[name] = attribute
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Sends an HTTP request without storing the request or its response in the plan or state, for example to fetch a secret",
+		Attributes:  attributes,
+	}
+}
+
+func (c *curl2EphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var config curl2EphemeralModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prepared, diags := prepareRequest(ctx, c.client, c.providerData, config.curl2RequestModel, requestOverrides{})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	requestCtx, cancel := prepared.withTimeout(ctx)
+	defer cancel()
+
+	r, responseData, diags := prepared.send(requestCtx, config.URI.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if detail := prepared.unexpectedStatus(r, responseData); detail != "" {
+		resp.Diagnostics.AddError("Unexpected response status", detail)
+		return
+	}
+
+	config.Response, diags = prepared.response(ctx, r, responseData)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.Result.Set(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (c *curl2EphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c.providerData = req.ProviderData.(*curl2ProviderData)
+	c.client = c.providerData.client
+}
+
+// ephemeralAttributes converts attribute specs into ephemeral resource schema attributes.
+func ephemeralAttributes(specs map[string]attributeSpec) map[string]schema.Attribute {
+	attributes := map[string]schema.Attribute{}
+	for name, spec := range specs {
+		switch spec.kind {
+		case stringAttribute:
+			attributes[name] = schema.StringAttribute{
+				Description: spec.description,
+				Required:    spec.required,
+				Optional:    !spec.required,
+				Sensitive:   spec.sensitive,
+			}
+		case int64Attribute:
+			attributes[name] = schema.Int64Attribute{
+				Description: spec.description,
+				Required:    spec.required,
+				Optional:    !spec.required,
+				Sensitive:   spec.sensitive,
+			}
+		case stringListAttribute:
+			attributes[name] = schema.ListAttribute{
+				Description: spec.description,
+				ElementType: types.StringType,
+				Required:    spec.required,
+				Optional:    !spec.required,
+				Sensitive:   spec.sensitive,
+			}
+		case stringMapAttribute:
+			attributes[name] = schema.MapAttribute{
+				Description: spec.description,
+				ElementType: types.StringType,
+				Required:    spec.required,
+				Optional:    !spec.required,
+				Sensitive:   spec.sensitive,
+			}
+		case nestedAttribute:
+			attributes[name] = schema.SingleNestedAttribute{
+				Description: spec.description,
+				Required:    spec.required,
+				Optional:    !spec.required,
+				Sensitive:   spec.sensitive,
+				Attributes:  ephemeralAttributes(spec.attributes),
+			}
+		}
+	}
+	return attributes
+}
@@ -0,0 +1,81 @@
+package curl2
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &auth0TokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &auth0TokenEphemeralResource{}
+)
+
+func NewAuth0TokenEphemeralResource() ephemeral.EphemeralResource {
+	return &auth0TokenEphemeralResource{}
+}
+
+type auth0TokenEphemeralResource struct {
+	providerData *curl2ProviderData
+}
+
+func (a *auth0TokenEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	a.providerData = req.ProviderData.(*curl2ProviderData)
+}
+
+func (a *auth0TokenEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_auth0_token"
+}
+
+func (a *auth0TokenEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches Auth0 Token without storing it in the plan or state",
+		Attributes: map[string]schema.Attribute{
+			"audience": schema.StringAttribute{
+				Description: "The audience for the token, which is your API. Example: \"https://xyz.com\"",
+				Required:    true,
+			},
+			"organization": schema.StringAttribute{
+				Description: "Organization name or ID the token is requested for.",
+				Optional:    true,
+			},
+			"extra_params": schema.MapAttribute{
+				Description: "Additional parameters sent in the token request body.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"response": schema.ObjectAttribute{
+				AttributeTypes: auth0TokenResponseAttrTypes(),
+				Description:    "Token response. `expires_at` is in RFC 3339 format.",
+				Computed:       true,
+				Sensitive:      true,
+			},
+		},
+	}
+}
+
+func (a *auth0TokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var config auth0TokenDataModelRequest
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config.Response, diags = auth0TokenResponse(ctx, a.providerData, config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.Result.Set(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,73 @@
+package curl2
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &azureADTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &azureADTokenEphemeralResource{}
+)
+
+func NewAzureADTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &azureADTokenEphemeralResource{}
+}
+
+type azureADTokenEphemeralResource struct {
+	providerData *curl2ProviderData
+}
+
+func (a *azureADTokenEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	a.providerData = req.ProviderData.(*curl2ProviderData)
+}
+
+func (a *azureADTokenEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_azuread_token"
+}
+
+func (a *azureADTokenEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches Azure AD Token without storing it in the plan or state",
+		Attributes: map[string]schema.Attribute{
+			"scopes": schema.ListAttribute{
+				ElementType: types.StringType,
+				Description: "The value passed for the scope parameter in this request should be the resource identifier (application ID URI) of the resource you want, affixed with the .default suffix. Example: [\"https://graph.microsoft.com/.default\"]",
+				Required:    true,
+			},
+			"response": schema.ObjectAttribute{
+				AttributeTypes: azureADTokenResponseAttrTypes(),
+				Description:    "Token response, along with the credential type and the client, tenant and object id of the identity the token was issued to.",
+				Computed:       true,
+				Sensitive:      true,
+			},
+		},
+	}
+}
+
+func (a *azureADTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var config azureADTokenDataModelRequest
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config.Response, diags = azureADTokenResponse(ctx, a.providerData, config.Scopes)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.Result.Set(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,50 @@
+package curl2
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var (
+	_ function.Function = &basicAuthHeaderFunction{}
+)
+
+func NewBasicAuthHeaderFunction() function.Function {
+	return &basicAuthHeaderFunction{}
+}
+
+type basicAuthHeaderFunction struct{}
+
+func (f *basicAuthHeaderFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "basic_auth_header"
+}
+
+func (f *basicAuthHeaderFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Builds the value of a Basic Authorization header",
+		Description: "Returns `Basic ` followed by the base64 encoded `user:pass`, as sent with the Basic auth type.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "user",
+				Description: "Username.",
+			},
+			function.StringParameter{
+				Name:        "pass",
+				Description: "Password.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *basicAuthHeaderFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var user, pass string
+
+	resp.Error = req.Arguments.Get(ctx, &user, &pass)
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, "Basic "+base64.StdEncoding.EncodeToString([]byte(user+":"+pass)))
+}
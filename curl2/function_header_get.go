@@ -0,0 +1,125 @@
+package curl2
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"sort"
+	"strings"
+)
+
+var (
+	_ function.Function = &headerGetFunction{}
+)
+
+func NewHeaderGetFunction() function.Function {
+	return &headerGetFunction{}
+}
+
+type headerGetFunction struct{}
+
+func (f *headerGetFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "header_get"
+}
+
+func (f *headerGetFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Returns a header value, matching its name case-insensitively",
+		Description: "Returns the value of the header `name` from `headers`, ignoring the case of the header names. `headers` is either a map of string, such as the `headers` attribute of a request, or a map of list of string, such as `response.headers`. Multiple values are joined with `, `. Returns null if the header is not present.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "headers",
+				Description: "Headers as a map of string or a map of list of string.",
+			},
+			function.StringParameter{
+				Name:        "name",
+				Description: "Header name.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *headerGetFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var headers types.Dynamic
+	var name string
+
+	resp.Error = req.Arguments.Get(ctx, &headers, &name)
+	if resp.Error != nil {
+		return
+	}
+
+	var elements map[string]attr.Value
+	switch v := headers.UnderlyingValue().(type) {
+	case types.Map:
+		elements = v.Elements()
+	case types.Object:
+		elements = v.Attributes()
+	default:
+		resp.Error = function.NewArgumentFuncError(0, "headers must be a map of string or a map of list of string")
+		return
+	}
+
+	// Header names are visited in order so a header set twice with different cases gives a stable result.
+	names := make([]string, 0, len(elements))
+	for headerName := range elements {
+		names = append(names, headerName)
+	}
+	sort.Strings(names)
+
+	var values []string
+	for _, headerName := range names {
+		if !strings.EqualFold(headerName, name) {
+			continue
+		}
+
+		headerValues, err := headerStrings(elements[headerName])
+		if err != nil {
+			resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("header %q %s", headerName, err.Error()))
+			return
+		}
+		values = append(values, headerValues...)
+	}
+
+	if values == nil {
+		resp.Error = resp.Result.Set(ctx, types.StringNull())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, strings.Join(values, ", "))
+}
+
+// headerStrings returns the values of a header given as a string or a list, tuple or set of strings.
+func headerStrings(value attr.Value) ([]string, error) {
+	if value.IsNull() {
+		return nil, nil
+	}
+	if value.IsUnknown() {
+		return nil, fmt.Errorf("is unknown")
+	}
+
+	var elements []attr.Value
+	switch v := value.(type) {
+	case types.String:
+		return []string{v.ValueString()}, nil
+	case types.List:
+		elements = v.Elements()
+	case types.Tuple:
+		elements = v.Elements()
+	case types.Set:
+		elements = v.Elements()
+	default:
+		return nil, fmt.Errorf("must be a string or a list of string")
+	}
+
+	var values []string
+	for _, element := range elements {
+		elementValues, err := headerStrings(element)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, elementValues...)
+	}
+	return values, nil
+}
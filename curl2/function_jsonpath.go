@@ -0,0 +1,66 @@
+package curl2
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/tidwall/gjson"
+)
+
+var (
+	_ function.Function = &jsonPathFunction{}
+)
+
+func NewJSONPathFunction() function.Function {
+	return &jsonPathFunction{}
+}
+
+type jsonPathFunction struct{}
+
+func (f *jsonPathFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "jsonpath"
+}
+
+func (f *jsonPathFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Returns the value at a path of a JSON document",
+		Description: "Returns the value at a JSONPath (`$.items[0].id`) or gjson (`items.0.id`) path of a JSON document, in the format of the `extract` attribute of `curl2`. Strings are returned as is, other values as JSON. Fails if the path does not exist.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "body",
+				Description: "JSON document, such as `response.body` of `curl2`.",
+			},
+			function.StringParameter{
+				Name:        "expr",
+				Description: "Path of the value.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *jsonPathFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var body, expr string
+
+	resp.Error = req.Arguments.Get(ctx, &body, &expr)
+	if resp.Error != nil {
+		return
+	}
+
+	if !gjson.Valid(body) {
+		resp.Error = function.NewArgumentFuncError(0, "body is not valid JSON")
+		return
+	}
+
+	result, found, err := lookupJSON([]byte(body), expr)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+	if !found {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("path %q does not exist in the JSON document", expr))
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, jsonResultString(result))
+}
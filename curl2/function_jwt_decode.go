@@ -0,0 +1,115 @@
+package curl2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"math/big"
+)
+
+var (
+	_ function.Function = &jwtDecodeFunction{}
+)
+
+func NewJWTDecodeFunction() function.Function {
+	return &jwtDecodeFunction{}
+}
+
+type jwtDecodeFunction struct{}
+
+func (f *jwtDecodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "jwt_decode"
+}
+
+func (f *jwtDecodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Returns the claims of a JSON Web Token",
+		Description: "Decodes the payload of a JSON Web Token into an object of its claims. The signature is not verified, so the claims must not be trusted for authorization decisions.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "token",
+				Description: "JSON Web Token, such as `response.token` of `curl2_azuread_token`.",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *jwtDecodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var token string
+
+	resp.Error = req.Arguments.Get(ctx, &token)
+	if resp.Error != nil {
+		return
+	}
+
+	claims, err := decodeJWTClaims(token)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	value, err := jsonValue(claims)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, types.DynamicValue(value))
+}
+
+// jsonValue converts a value decoded with json.Decoder.UseNumber into the matching Terraform value.
+// Objects become objects, arrays tuples and null a null string.
+func jsonValue(value interface{}) (attr.Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return types.StringNull(), nil
+	case bool:
+		return types.BoolValue(v), nil
+	case string:
+		return types.StringValue(v), nil
+	case json.Number:
+		number, ok := new(big.Float).SetString(v.String())
+		if !ok {
+			return nil, fmt.Errorf("invalid number %q", v.String())
+		}
+		return types.NumberValue(number), nil
+	case []interface{}:
+		elementTypes := make([]attr.Type, len(v))
+		elements := make([]attr.Value, len(v))
+		for i, element := range v {
+			elementValue, err := jsonValue(element)
+			if err != nil {
+				return nil, err
+			}
+			elementTypes[i] = elementValue.Type(context.Background())
+			elements[i] = elementValue
+		}
+		tuple, diags := types.TupleValue(elementTypes, elements)
+		if diags.HasError() {
+			return nil, fmt.Errorf("unable to build tuple: %v", diags)
+		}
+		return tuple, nil
+	case map[string]interface{}:
+		attributeTypes := map[string]attr.Type{}
+		attributes := map[string]attr.Value{}
+		for name, attribute := range v {
+			attributeValue, err := jsonValue(attribute)
+			if err != nil {
+				return nil, err
+			}
+			attributeTypes[name] = attributeValue.Type(context.Background())
+			attributes[name] = attributeValue
+		}
+		object, diags := types.ObjectValue(attributeTypes, attributes)
+		if diags.HasError() {
+			return nil, fmt.Errorf("unable to build object: %v", diags)
+		}
+		return object, nil
+	default:
+		return nil, fmt.Errorf("unsupported JSON value of type %T", value)
+	}
+}
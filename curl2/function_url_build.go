@@ -0,0 +1,105 @@
+package curl2
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"net/url"
+	"strings"
+)
+
+var (
+	_ function.Function = &urlBuildFunction{}
+)
+
+func NewURLBuildFunction() function.Function {
+	return &urlBuildFunction{}
+}
+
+type urlBuildFunction struct{}
+
+func (f *urlBuildFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "url_build"
+}
+
+func (f *urlBuildFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Builds a URL from a base URL, a path and query parameters",
+		Description: "Appends `path` to the path of `base` and adds the `query_map` parameters to its query string. Each segment of `path` and every query parameter is escaped, so values may contain spaces, slashes in parameters or other reserved characters. Query parameters replace parameters of the same name in `base` and are sorted by name.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "base",
+				Description: "Base URL, which may already contain a path and query string.",
+			},
+			function.StringParameter{
+				Name:        "path",
+				Description: "Path appended to the base URL. Segments are separated by `/` and escaped one by one. Can be empty.",
+			},
+			function.MapParameter{
+				Name:           "query_map",
+				Description:    "Query parameters as a map of name to value. Can be null.",
+				ElementType:    types.StringType,
+				AllowNullValue: true,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *urlBuildFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var base, urlPath string
+	var queryMap types.Map
+
+	resp.Error = req.Arguments.Get(ctx, &base, &urlPath, &queryMap)
+	if resp.Error != nil {
+		return
+	}
+
+	query := map[string]string{}
+	if !queryMap.IsNull() {
+		resp.Error = function.FuncErrorFromDiags(ctx, queryMap.ElementsAs(ctx, &query, false))
+		if resp.Error != nil {
+			return
+		}
+	}
+
+	built, err := buildURL(base, urlPath, query)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, built)
+}
+
+// buildURL appends the escaped segments of urlPath to the path of base and sets the query parameters.
+func buildURL(base string, urlPath string, query map[string]string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+
+	if urlPath != "" {
+		segments := strings.Split(strings.TrimPrefix(urlPath, "/"), "/")
+		for i, segment := range segments {
+			segments[i] = url.PathEscape(segment)
+		}
+
+		escapedPath := strings.TrimSuffix(u.EscapedPath(), "/") + "/" + strings.Join(segments, "/")
+		u.Path, err = url.PathUnescape(escapedPath)
+		if err != nil {
+			return "", err
+		}
+		u.RawPath = escapedPath
+	}
+
+	if len(query) > 0 {
+		values := u.Query()
+		for _, name := range sortedKeys(query) {
+			values.Set(name, query[name])
+		}
+		u.RawQuery = values.Encode()
+	}
+
+	return u.String(), nil
+}
@@ -0,0 +1,452 @@
+package curl2
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const harFileEnv = "CURL2_HAR_FILE"
+
+// The HTTP Archive 1.2 format, see http://www.softwareishard.com/blog/har-12-spec/. Fields starting with an
+// underscore are custom fields, which the format allows.
+type harArchive struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Attempt         int         `json:"_attempt"`
+	Error           string      `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string         `json:"mimeType"`
+	Params   []harNameValue `json:"params"`
+	Text     string         `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+// harTimings are in milliseconds, -1 when the phase did not happen, for example when a connection was reused.
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// harTrailer closes the entries of an archive written by harRecorder. Entries are written in front of it, so the
+// file is a complete archive after every request.
+const harTrailer = "\n]}}\n"
+
+// harRecorder appends exchanges to an archive file. Every provider configuration runs in its own process, so the
+// file is opened and locked for each entry: the end of the archive is read under the lock and no process writes
+// over entries appended by another one.
+type harRecorder struct {
+	path string
+}
+
+// openHARRecorder returns the recorder of the file. Entries of an existing archive are kept, so the separate
+// provider processes of a plan and an apply write to a single archive. The file is checked and laid out here, so
+// an invalid archive is reported when the provider is configured.
+func openHARRecorder(path string) (*harRecorder, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder := &harRecorder{path: path}
+	if err := recorder.append(nil); err != nil {
+		return nil, err
+	}
+	return recorder, nil
+}
+
+// record writes the entry in front of the trailer, so only the new entry is written and the file stays a complete
+// archive when Terraform stops the provider.
+func (r *harRecorder) record(entry harEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return r.append(data)
+}
+
+// append writes the encoded entry in front of the trailer while holding an exclusive lock on the file, which is
+// closed before returning. A nil entry only lays out the file.
+func (r *harRecorder) append(entry []byte) error {
+	file, err := os.OpenFile(r.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := lockFile(file); err != nil {
+		return fmt.Errorf("unable to lock %s: %w", r.path, err)
+	}
+	defer unlockFile(file)
+
+	offset, empty, err := harAppendOffset(file)
+	if err != nil || entry == nil {
+		return err
+	}
+
+	separator := ",\n"
+	if empty {
+		separator = "\n"
+	}
+	_, err = file.WriteAt([]byte(separator+string(entry)+harTrailer), offset)
+	return err
+}
+
+// harAppendOffset returns where the next entry of the locked archive is written, the start of the trailer, and
+// whether the archive has no entries yet. Empty files and archives written by another tool are laid out first.
+func harAppendOffset(file *os.File) (int64, bool, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return 0, false, err
+	}
+	size := info.Size()
+
+	// The byte in front of the trailer opens the entries when there are none.
+	if size > int64(len(harTrailer)) {
+		tail := make([]byte, len(harTrailer)+1)
+		if _, err := file.ReadAt(tail, size-int64(len(tail))); err != nil {
+			return 0, false, err
+		}
+		if string(tail[1:]) == harTrailer {
+			return size - int64(len(harTrailer)), tail[0] == '[', nil
+		}
+	}
+
+	data, err := io.ReadAll(io.NewSectionReader(file, 0, size))
+	if err != nil {
+		return 0, false, err
+	}
+	var existing harArchive
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &existing); err != nil {
+			return 0, false, fmt.Errorf("%s exists and is not an HTTP Archive: %w", file.Name(), err)
+		}
+	}
+
+	offset, err := rewriteHAR(file, existing.Log.Entries)
+	return offset, len(existing.Log.Entries) == 0, err
+}
+
+// rewriteHAR writes a new archive holding the entries and returns the offset of its trailer.
+func rewriteHAR(file *os.File, entries []harEntry) (int64, error) {
+	creator, err := json.Marshal(harCreator{Name: "terraform-provider-curl2", Version: providerVersion()})
+	if err != nil {
+		return 0, err
+	}
+
+	var b strings.Builder
+	b.WriteString(`{"log":{"version":"1.2","creator":` + string(creator) + `,"entries":[`)
+	for i, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return 0, err
+		}
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n" + string(data))
+	}
+	offset := int64(b.Len())
+	b.WriteString(harTrailer)
+
+	if err := file.Truncate(0); err != nil {
+		return 0, err
+	}
+	_, err = file.WriteAt([]byte(b.String()), 0)
+	return offset, err
+}
+
+func providerVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+// harTransport records the exchanges sent through the wrapped transport, with secrets masked as in the logs.
+type harTransport struct {
+	transport http.RoundTripper
+	recorder  *harRecorder
+	opts      logOptions
+}
+
+func (t *harTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := peekBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var phases harPhases
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), phases.trace()))
+
+	start := time.Now()
+	resp, err := t.transport.RoundTrip(req)
+	responseStart := time.Now()
+
+	var responseBody []byte
+	if err == nil {
+		responseBody, err = peekBody(&resp.Body)
+	}
+	end := time.Now()
+
+	entry := harEntry{
+		StartedDateTime: start.Format(time.RFC3339Nano),
+		Request:         t.request(req, requestBody),
+		Response:        harResponse{Cookies: []harNameValue{}, Headers: []harNameValue{}, HeadersSize: -1, BodySize: -1},
+		Timings:         phases.timings(start, responseStart, end),
+		Attempt:         attemptNumber(req.Context()),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Response = t.response(resp, responseBody)
+	}
+	entry.Time = entry.Timings.total()
+
+	if recordErr := t.recorder.record(entry); recordErr != nil {
+		tflog.Warn(req.Context(), "Unable to write HTTP Archive", map[string]any{"har_file": t.recorder.path, "error": recordErr.Error()})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (t *harTransport) request(req *http.Request, body []byte) harRequest {
+	request := harRequest{
+		Method:      req.Method,
+		URL:         redactURL(req.URL),
+		HTTPVersion: req.Proto,
+		Cookies:     []harNameValue{},
+		Headers:     harHeaders(t.opts.headers(req.Header)),
+		QueryString: []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+
+	query := req.URL.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range query[name] {
+			if sensitiveKeyPattern.MatchString(name) {
+				value = redactedValue
+			}
+			request.QueryString = append(request.QueryString, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	if len(body) > 0 {
+		request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Params:   []harNameValue{},
+			Text:     t.opts.body(body),
+		}
+	}
+
+	return request
+}
+
+func (t *harTransport) response(resp *http.Response, body []byte) harResponse {
+	return harResponse{
+		Status:      resp.StatusCode,
+		StatusText:  strings.TrimSpace(strings.TrimPrefix(resp.Status, fmt.Sprint(resp.StatusCode))),
+		HTTPVersion: resp.Proto,
+		Cookies:     []harNameValue{},
+		Headers:     harHeaders(t.opts.headers(resp.Header)),
+		Content: harContent{
+			Size:     len(body),
+			MimeType: resp.Header.Get("Content-Type"),
+			Text:     t.opts.body(body),
+		},
+		RedirectURL: resp.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+}
+
+func harHeaders(headers map[string]string) []harNameValue {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make([]harNameValue, len(names))
+	for i, name := range names {
+		values[i] = harNameValue{Name: name, Value: headers[name]}
+	}
+	return values
+}
+
+// harPhases collects the moments of a round trip reported by httptrace.
+type harPhases struct {
+	mu                               sync.Mutex
+	dnsStart, dnsDone                time.Time
+	connectStart, connectDone        time.Time
+	tlsStart, tlsDone                time.Time
+	gotConn, wroteRequest, firstByte time.Time
+}
+
+func (p *harPhases) trace() *httptrace.ClientTrace {
+	at := func(moment *time.Time) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if moment.IsZero() {
+			*moment = time.Now()
+		}
+	}
+
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { at(&p.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { at(&p.dnsDone) },
+		ConnectStart:         func(string, string) { at(&p.connectStart) },
+		ConnectDone:          func(string, string, error) { at(&p.connectDone) },
+		TLSHandshakeStart:    func() { at(&p.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { at(&p.tlsDone) },
+		GotConn:              func(httptrace.GotConnInfo) { at(&p.gotConn) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { at(&p.wroteRequest) },
+		GotFirstResponseByte: func() { at(&p.firstByte) },
+	}
+}
+
+// timings splits the round trip into the HAR phases. Phases that did not happen are -1, send, wait and receive
+// are always set.
+func (p *harPhases) timings(start, responseStart, end time.Time) harTimings {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	timings := harTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1}
+	if !p.dnsStart.IsZero() && !p.dnsDone.IsZero() {
+		timings.DNS = milliseconds(p.dnsDone.Sub(p.dnsStart))
+	}
+	if !p.connectStart.IsZero() && !p.connectDone.IsZero() {
+		timings.Connect = milliseconds(p.connectDone.Sub(p.connectStart))
+	}
+	if !p.tlsStart.IsZero() && !p.tlsDone.IsZero() {
+		timings.SSL = milliseconds(p.tlsDone.Sub(p.tlsStart))
+		// HAR counts the TLS handshake as part of connect.
+		if timings.Connect >= 0 {
+			timings.Connect += timings.SSL
+		}
+	}
+
+	sendStart := p.gotConn
+	if sendStart.IsZero() {
+		sendStart = start
+	}
+	if timings.DNS < 0 && timings.Connect < 0 {
+		timings.Blocked = milliseconds(sendStart.Sub(start))
+	}
+
+	sendEnd := p.wroteRequest
+	if sendEnd.IsZero() {
+		sendEnd = sendStart
+	}
+	waitEnd := p.firstByte
+	if waitEnd.IsZero() {
+		waitEnd = responseStart
+	}
+	if waitEnd.Before(sendEnd) {
+		waitEnd = sendEnd
+	}
+
+	timings.Send = milliseconds(sendEnd.Sub(sendStart))
+	timings.Wait = milliseconds(waitEnd.Sub(sendEnd))
+	timings.Receive = milliseconds(end.Sub(waitEnd))
+	return timings
+}
+
+// total is the time of the entry, the sum of the phases that happened. SSL is already part of connect.
+func (t harTimings) total() float64 {
+	total := 0.0
+	for _, phase := range []float64{t.Blocked, t.DNS, t.Connect, t.Send, t.Wait, t.Receive} {
+		if phase > 0 {
+			total += phase
+		}
+	}
+	return math.Round(total*1000) / 1000
+}
+
+func milliseconds(d time.Duration) float64 {
+	if d < 0 {
+		return 0
+	}
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package curl2
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile waits for an exclusive lock on the file. The lock is held by the open file, so recorders of the same
+// process are serialized as well.
+func lockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+}
+
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package curl2
+
+import (
+	"golang.org/x/sys/windows"
+	"os"
+)
+
+// lockFile waits for an exclusive lock on the first byte of the file, which every writer of the archive locks.
+func lockFile(file *os.File) error {
+	return windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, &windows.Overlapped{})
+}
+
+func unlockFile(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, &windows.Overlapped{})
+}
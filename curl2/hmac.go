@@ -0,0 +1,263 @@
+package curl2
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	hmacEncodingHex    = "hex"
+	hmacEncodingBase64 = "base64"
+
+	hmacTimestampUnix    = "unix"
+	hmacTimestampUnixMS  = "unix_ms"
+	hmacTimestampRFC3339 = "rfc3339"
+)
+
+var defaultHMACComponents = []string{"method", "path", "timestamp", "body_digest"}
+
+var hmacAlgorithms = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// hmacModel maps the `hmac` attribute of the curl2 data source and request resource.
+type hmacModel struct {
+	Key              types.String `tfsdk:"key"`
+	Algorithm        types.String `tfsdk:"algorithm"`
+	SignedComponents types.List   `tfsdk:"signed_components"`
+	Separator        types.String `tfsdk:"separator"`
+	TimestampHeader  types.String `tfsdk:"timestamp_header"`
+	TimestampFormat  types.String `tfsdk:"timestamp_format"`
+	NonceHeader      types.String `tfsdk:"nonce_header"`
+	SignatureHeader  types.String `tfsdk:"signature_header"`
+	SignaturePrefix  types.String `tfsdk:"signature_prefix"`
+	Encoding         types.String `tfsdk:"encoding"`
+}
+
+// hmacConfig is the resolved form of hmacModel with defaults applied.
+type hmacConfig struct {
+	Key              string
+	Algorithm        string
+	SignedComponents []string
+	Separator        string
+	TimestampHeader  string
+	TimestampFormat  string
+	NonceHeader      string
+	SignatureHeader  string
+	SignaturePrefix  string
+	Encoding         string
+}
+
+// hmacAttributeDescriptions documents the string attributes of `hmac`.
+var hmacAttributeDescriptions = map[string]string{
+	"key":              "Shared secret used as the HMAC key.",
+	"algorithm":        "Hash algorithm, one of `sha256`, `sha512` or `sha1`. Defaults to `sha256`.",
+	"separator":        "Separator placed between the signed components. Defaults to a newline.",
+	"timestamp_header": "Header carrying the request timestamp. Defaults to `X-Timestamp`.",
+	"timestamp_format": "Format of the timestamp, one of `unix`, `unix_ms` or `rfc3339`. Defaults to `unix`.",
+	"nonce_header":     "Header carrying a random nonce generated for every attempt of a request, retries included. No nonce is sent when not set.",
+	"signature_header": "Header carrying the signature. Defaults to `X-Signature`.",
+	"signature_prefix": "Text placed before the signature in the signature header, for example `HMAC-SHA256 `.",
+	"encoding":         "Encoding of the signature and of `body_digest`, `hex` or `base64`. Defaults to `hex`.",
+}
+
+const hmacSignedComponentsDescription = "Components joined by `separator` to build the string to sign, in order. One of `method`, `path`, `query`, `host`, `timestamp`, `nonce`, `body`, `body_digest` or `header:<name>`. Defaults to `[\"method\", \"path\", \"timestamp\", \"body_digest\"]`."
+
+// hmacConfigFromObject decodes and validates an `hmac` attribute.
+func hmacConfigFromObject(ctx context.Context, object types.Object) (hmacConfig, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if object.IsNull() || object.IsUnknown() {
+		diags.AddAttributeError(
+			path.Root("hmac"),
+			"Missing HMAC Configuration",
+			"hmac must be set to use the HMAC auth type",
+		)
+		return hmacConfig{}, diags
+	}
+
+	var m hmacModel
+	diags.Append(object.As(ctx, &m, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return hmacConfig{}, diags
+	}
+
+	config := hmacConfig{
+		Key:              m.Key.ValueString(),
+		Algorithm:        stringOrDefault(m.Algorithm, "sha256"),
+		SignedComponents: defaultHMACComponents,
+		Separator:        stringOrDefault(m.Separator, "\n"),
+		TimestampHeader:  stringOrDefault(m.TimestampHeader, "X-Timestamp"),
+		TimestampFormat:  stringOrDefault(m.TimestampFormat, hmacTimestampUnix),
+		NonceHeader:      m.NonceHeader.ValueString(),
+		SignatureHeader:  stringOrDefault(m.SignatureHeader, "X-Signature"),
+		SignaturePrefix:  m.SignaturePrefix.ValueString(),
+		Encoding:         stringOrDefault(m.Encoding, hmacEncodingHex),
+	}
+	if !m.SignedComponents.IsNull() && !m.SignedComponents.IsUnknown() {
+		config.SignedComponents = nil
+		diags.Append(m.SignedComponents.ElementsAs(ctx, &config.SignedComponents, false)...)
+		if diags.HasError() {
+			return hmacConfig{}, diags
+		}
+	}
+
+	if err := config.validate(); err != nil {
+		diags.AddAttributeError(
+			path.Root("hmac"),
+			"Invalid HMAC Configuration",
+			err.Error(),
+		)
+	}
+
+	return config, diags
+}
+
+func (c hmacConfig) validate() error {
+	if c.Key == "" {
+		return fmt.Errorf("key must be provided")
+	}
+	if _, ok := hmacAlgorithms[c.Algorithm]; !ok {
+		return fmt.Errorf("algorithm must be one of sha256, sha512 or sha1, got: %s", c.Algorithm)
+	}
+	if c.Encoding != hmacEncodingHex && c.Encoding != hmacEncodingBase64 {
+		return fmt.Errorf("encoding must be one of hex or base64, got: %s", c.Encoding)
+	}
+	switch c.TimestampFormat {
+	case hmacTimestampUnix, hmacTimestampUnixMS, hmacTimestampRFC3339:
+	default:
+		return fmt.Errorf("timestamp_format must be one of unix, unix_ms or rfc3339, got: %s", c.TimestampFormat)
+	}
+	if len(c.SignedComponents) == 0 {
+		return fmt.Errorf("signed_components cannot be empty")
+	}
+
+	for _, component := range c.SignedComponents {
+		switch {
+		case component == "nonce" && c.NonceHeader == "":
+			return fmt.Errorf("nonce_header must be set to sign the nonce")
+		case component == "method", component == "path", component == "query", component == "host",
+			component == "timestamp", component == "nonce", component == "body", component == "body_digest":
+		case strings.HasPrefix(component, "header:") && len(component) > len("header:"):
+		default:
+			return fmt.Errorf("unknown signed component %q", component)
+		}
+	}
+	return nil
+}
+
+type requestSignerKey struct{}
+
+// requestSigner signs a request again before it is retried, so every attempt carries its own timestamp and nonce
+// instead of replaying those of the first one.
+type requestSigner func(req *http.Request, now time.Time) error
+
+// signRetry signs the retried attempt of a request carrying a requestSigner.
+func signRetry(req *http.Request, retry int) {
+	sign, ok := req.Context().Value(requestSignerKey{}).(requestSigner)
+	if !ok || retry == 0 {
+		return
+	}
+	if err := sign(req, time.Now()); err != nil {
+		tflog.Warn(req.Context(), "Unable to sign retried request", map[string]any{"error": err.Error()})
+	}
+}
+
+// signHMAC sets the timestamp, nonce and signature headers on req. Headers used as signed components must
+// already be set on the request.
+func signHMAC(req *http.Request, body []byte, config hmacConfig, now time.Time) error {
+	newHash := hmacAlgorithms[config.Algorithm]
+
+	var timestamp string
+	switch config.TimestampFormat {
+	case hmacTimestampUnixMS:
+		timestamp = strconv.FormatInt(now.UnixMilli(), 10)
+	case hmacTimestampRFC3339:
+		timestamp = now.UTC().Format(time.RFC3339)
+	default:
+		timestamp = strconv.FormatInt(now.Unix(), 10)
+	}
+	if config.TimestampHeader != "" {
+		req.Header.Set(config.TimestampHeader, timestamp)
+	}
+
+	var nonce string
+	if config.NonceHeader != "" {
+		nonceBytes := make([]byte, 16)
+		if _, err := rand.Read(nonceBytes); err != nil {
+			return fmt.Errorf("unable to generate nonce: %w", err)
+		}
+		nonce = hex.EncodeToString(nonceBytes)
+		req.Header.Set(config.NonceHeader, nonce)
+	}
+
+	bodyHash := newHash()
+	bodyHash.Write(body)
+
+	components := make([]string, 0, len(config.SignedComponents))
+	for _, component := range config.SignedComponents {
+		switch component {
+		case "method":
+			components = append(components, req.Method)
+		case "path":
+			components = append(components, req.URL.EscapedPath())
+		case "query":
+			components = append(components, req.URL.RawQuery)
+		case "host":
+			host := req.Host
+			if host == "" {
+				host = req.URL.Host
+			}
+			components = append(components, host)
+		case "timestamp":
+			components = append(components, timestamp)
+		case "nonce":
+			components = append(components, nonce)
+		case "body":
+			components = append(components, string(body))
+		case "body_digest":
+			components = append(components, encodeHMAC(bodyHash.Sum(nil), config.Encoding))
+		default:
+			components = append(components, req.Header.Get(strings.TrimPrefix(component, "header:")))
+		}
+	}
+
+	mac := hmac.New(newHash, []byte(config.Key))
+	mac.Write([]byte(strings.Join(components, config.Separator)))
+	req.Header.Set(config.SignatureHeader, config.SignaturePrefix+encodeHMAC(mac.Sum(nil), config.Encoding))
+
+	return nil
+}
+
+func encodeHMAC(sum []byte, encoding string) string {
+	if encoding == hmacEncodingBase64 {
+		return base64.StdEncoding.EncodeToString(sum)
+	}
+	return hex.EncodeToString(sum)
+}
+
+// stringOrDefault returns the configured value, or fallback when it is not set.
+func stringOrDefault(value types.String, fallback string) string {
+	if value.IsNull() || value.IsUnknown() {
+		return fallback
+	}
+	return value.ValueString()
+}
@@ -0,0 +1,83 @@
+package curl2
+
+import (
+	"fmt"
+	"github.com/tidwall/gjson"
+	"strings"
+)
+
+// toGJSONPath converts a JSONPath expression such as `$.items[0]['display.name']` into the equivalent
+// gjson path `items.0.display\.name`. Expressions not starting with `$` are treated as gjson paths.
+func toGJSONPath(expr string) (string, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "$") {
+		return expr, nil
+	}
+
+	var parts []string
+	rest := expr[1:]
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return "", fmt.Errorf("invalid JSONPath %q: empty member name", expr)
+			}
+			parts = append(parts, escapeGJSONKey(rest[:end]))
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return "", fmt.Errorf("invalid JSONPath %q: missing closing bracket", expr)
+			}
+			selector := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			switch {
+			case selector == "*":
+				parts = append(parts, "#")
+			case len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0]:
+				parts = append(parts, escapeGJSONKey(selector[1:len(selector)-1]))
+			case selector != "" && strings.Trim(selector, "0123456789") == "":
+				parts = append(parts, selector)
+			default:
+				return "", fmt.Errorf("invalid JSONPath %q: unsupported selector [%s]", expr, selector)
+			}
+		default:
+			return "", fmt.Errorf("invalid JSONPath %q: unexpected character %q", expr, rest[0])
+		}
+	}
+
+	if len(parts) == 0 {
+		return "@this", nil
+	}
+	return strings.Join(parts, "."), nil
+}
+
+func escapeGJSONKey(key string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ".", `\.`, "*", `\*`, "?", `\?`, "|", `\|`, "#", `\#`, "@", `\@`)
+	return replacer.Replace(key)
+}
+
+// lookupJSON returns the value found at expr, which may be a JSONPath or gjson path, in a JSON document.
+func lookupJSON(body []byte, expr string) (gjson.Result, bool, error) {
+	path, err := toGJSONPath(expr)
+	if err != nil {
+		return gjson.Result{}, false, err
+	}
+
+	result := gjson.GetBytes(body, path)
+	return result, result.Exists(), nil
+}
+
+// jsonResultString renders strings as their value and every other JSON value in its JSON form.
+func jsonResultString(result gjson.Result) string {
+	if result.Type == gjson.String {
+		return result.String()
+	}
+	return result.Raw
+}
@@ -0,0 +1,33 @@
+package curl2
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// decodeJWTClaims returns the claims of a JSON Web Token without verifying its signature.
+func decodeJWTClaims(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("token is not a JWT: expected 3 parts, got %d", len(parts))
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode JWT payload: %w", err)
+	}
+
+	// Numbers are kept as json.Number so large numeric claims are not rounded.
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var claims map[string]interface{}
+	if err = decoder.Decode(&claims); err != nil {
+		return nil, fmt.Errorf("unable to parse JWT claims: %w", err)
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,360 @@
+package curl2
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tidwall/gjson"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// logSubsystem is the tflog subsystem of the HTTP traffic, its level is set with TF_LOG_PROVIDER_CURL2_HTTP.
+	logSubsystem        = "http"
+	logSubsystemEnv     = "TF_LOG_PROVIDER_CURL2_HTTP"
+	providerLogEnv      = "TF_LOG_PROVIDER_CURL2"
+	defaultLogBodyBytes = 4096
+)
+
+var logLevels = map[string]bool{"TRACE": true, "DEBUG": true, "INFO": true, "WARN": true, "ERROR": true, "OFF": true}
+
+// logOptions controls what the HTTP traffic logs show.
+type logOptions struct {
+	// maskHeaders match the names of the headers masked on top of the sensitive ones.
+	maskHeaders []*regexp.Regexp
+	// maskJSONPaths are JSONPath or gjson paths masked in JSON bodies.
+	maskJSONPaths []string
+	// maxBodyBytes truncates logged bodies, 0 leaves bodies out.
+	maxBodyBytes int
+}
+
+// logOptionsFromObject reads the provider logging block, bodies are logged up to the default size when it is not set.
+func logOptionsFromObject(ctx context.Context, object types.Object) (logOptions, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	opts := logOptions{maxBodyBytes: defaultLogBodyBytes}
+	if object.IsNull() || object.IsUnknown() {
+		return opts, diags
+	}
+
+	var m loggingModel
+	diags.Append(object.As(ctx, &m, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return opts, diags
+	}
+
+	var maskHeaders []string
+	if !m.MaskHeaders.IsNull() && !m.MaskHeaders.IsUnknown() {
+		diags.Append(m.MaskHeaders.ElementsAs(ctx, &maskHeaders, false)...)
+	}
+	if !m.MaskJSONPaths.IsNull() && !m.MaskJSONPaths.IsUnknown() {
+		diags.Append(m.MaskJSONPaths.ElementsAs(ctx, &opts.maskJSONPaths, false)...)
+	}
+	if diags.HasError() {
+		return opts, diags
+	}
+
+	for _, name := range maskHeaders {
+		// Only `*` is special in a header pattern.
+		pattern := strings.ReplaceAll(regexp.QuoteMeta(name), `\*`, ".*")
+		opts.maskHeaders = append(opts.maskHeaders, regexp.MustCompile("(?i)^"+pattern+"$"))
+	}
+
+	for _, expr := range opts.maskJSONPaths {
+		if _, err := toGJSONPath(expr); err != nil {
+			diags.AddAttributeError(
+				path.Root("logging").AtName("mask_json_paths"),
+				"Invalid Logging Mask",
+				err.Error(),
+			)
+			return opts, diags
+		}
+	}
+
+	if !m.MaxBodyBytes.IsNull() && !m.MaxBodyBytes.IsUnknown() {
+		if m.MaxBodyBytes.ValueInt64() < 0 {
+			diags.AddAttributeError(
+				path.Root("logging").AtName("max_body_bytes"),
+				"Invalid Logging Max Body Bytes",
+				"Max body bytes cannot be negative",
+			)
+			return opts, diags
+		}
+		opts.maxBodyBytes = int(m.MaxBodyBytes.ValueInt64())
+	}
+
+	return opts, diags
+}
+
+type requestAttemptKey struct{}
+
+// requestAttempt is shared by the retry hook and the transport to number the attempts of a request.
+type requestAttempt struct {
+	number int
+}
+
+// attemptNumber returns the 1-based attempt of the request, 1 for requests sent outside of the retrying client.
+func attemptNumber(ctx context.Context) int {
+	if attempt, ok := ctx.Value(requestAttemptKey{}).(*requestAttempt); ok && attempt.number > 0 {
+		return attempt.number
+	}
+	return 1
+}
+
+// logRequestAttempt records the attempt about to be sent and logs the retries.
+func logRequestAttempt(_ retryablehttp.Logger, req *http.Request, retry int) {
+	if attempt, ok := req.Context().Value(requestAttemptKey{}).(*requestAttempt); ok {
+		attempt.number = retry + 1
+	}
+	if retry == 0 {
+		return
+	}
+
+	ctx := newLogSubsystem(req.Context())
+	tflog.SubsystemDebug(ctx, logSubsystem, "Retrying HTTP request", map[string]any{
+		"http_method": req.Method,
+		"url":         redactURL(req.URL),
+		"attempt":     retry + 1,
+	})
+}
+
+func newLogSubsystem(ctx context.Context) context.Context {
+	return tflog.NewSubsystem(ctx, logSubsystem, tflog.WithLevelFromEnv(logSubsystemEnv), tflog.WithRootFields())
+}
+
+// loggingTransport logs every request sent and response received through the wrapped transport. Requests and
+// responses are logged at DEBUG, their headers and bodies at TRACE, with secrets masked.
+type loggingTransport struct {
+	transport http.RoundTripper
+	opts      logOptions
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := newLogSubsystem(req.Context())
+	fields := map[string]any{
+		"http_method": req.Method,
+		"url":         redactURL(req.URL),
+		"attempt":     attemptNumber(req.Context()),
+	}
+
+	// Bodies are only read when they are logged, and no further than they are logged.
+	logBodies := t.opts.maxBodyBytes > 0 && traceLogEnabled()
+
+	var requestBody []byte
+	var requestTruncated bool
+	if logBodies {
+		var err error
+		requestBody, requestTruncated, err = peekBodyPrefix(&req.Body, t.opts.maxBodyBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tflog.SubsystemDebug(ctx, logSubsystem, "Sending HTTP request", fields)
+	tflog.SubsystemTrace(ctx, logSubsystem, "HTTP request", withFields(fields, map[string]any{
+		"headers": t.opts.headers(req.Header),
+		"body":    t.opts.logBody(requestBody, requestTruncated),
+	}))
+
+	start := time.Now()
+	resp, err := t.transport.RoundTrip(req)
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		tflog.SubsystemDebug(ctx, logSubsystem, "HTTP request failed", withFields(fields, map[string]any{
+			"error":      err.Error(),
+			"latency_ms": latency,
+		}))
+		return nil, err
+	}
+
+	responseFields := withFields(fields, map[string]any{
+		"status_code": resp.StatusCode,
+		"latency_ms":  latency,
+	})
+	tflog.SubsystemDebug(ctx, logSubsystem, "Received HTTP response", responseFields)
+
+	var responseBody []byte
+	var responseTruncated bool
+	if logBodies {
+		responseBody, responseTruncated, err = peekBodyPrefix(&resp.Body, t.opts.maxBodyBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tflog.SubsystemTrace(ctx, logSubsystem, "HTTP response", withFields(responseFields, map[string]any{
+		"headers": t.opts.headers(resp.Header),
+		"body":    t.opts.logBody(responseBody, responseTruncated),
+	}))
+
+	return resp, nil
+}
+
+// peekBody reads the whole body and puts back a reader over the same bytes.
+func peekBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// peekBodyPrefix reads up to limit bytes of the body and puts them back in front of the rest of it. It reports
+// whether the body is longer than limit.
+func peekBodyPrefix(body *io.ReadCloser, limit int) ([]byte, bool, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, false, nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(*body, int64(limit)+1))
+	if err != nil {
+		(*body).Close()
+		return nil, false, err
+	}
+	*body = peekedBody{Reader: io.MultiReader(bytes.NewReader(data), *body), Closer: *body}
+	if len(data) > limit {
+		return data[:limit], true, nil
+	}
+	return data, false, nil
+}
+
+// peekedBody reads the peeked bytes followed by the rest of the body it closes.
+type peekedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// traceLogEnabled reports whether TRACE entries of the http subsystem reach the Terraform logs. The subsystem logs at
+// the level of TF_LOG_PROVIDER_CURL2_HTTP or TF_LOG_PROVIDER_CURL2, TRACE by default, and Terraform keeps provider
+// entries down to the level of TF_LOG_PROVIDER or TF_LOG.
+func traceLogEnabled() bool {
+	for _, name := range []string{logSubsystemEnv, providerLogEnv} {
+		// Unknown levels are ignored by the logger.
+		if level := logLevel(name); logLevels[level] {
+			if level != "TRACE" {
+				return false
+			}
+			break
+		}
+	}
+
+	// Terraform logs at TRACE when the level is unknown, JSON included.
+	level := logLevel("TF_LOG_PROVIDER")
+	if level == "" {
+		level = logLevel("TF_LOG")
+	}
+	return level != "" && (level == "TRACE" || !logLevels[level])
+}
+
+func logLevel(name string) string {
+	return strings.ToUpper(strings.TrimSpace(os.Getenv(name)))
+}
+
+func withFields(fields map[string]any, additional map[string]any) map[string]any {
+	merged := make(map[string]any, len(fields)+len(additional))
+	for key, value := range fields {
+		merged[key] = value
+	}
+	for key, value := range additional {
+		merged[key] = value
+	}
+	return merged
+}
+
+// headers returns the headers with the values of sensitive and configured headers masked.
+func (o logOptions) headers(header http.Header) map[string]string {
+	masked := make(map[string]string, len(header))
+	for name, values := range header {
+		if o.maskedHeader(name) {
+			masked[name] = redactedValue
+			continue
+		}
+		masked[name] = strings.Join(values, ", ")
+	}
+	return masked
+}
+
+func (o logOptions) maskedHeader(name string) bool {
+	if sensitiveKeyPattern.MatchString(name) {
+		return true
+	}
+	for _, pattern := range o.maskHeaders {
+		if pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// body returns the body with sensitive fields and the configured JSON paths masked, truncated to the size limit.
+func (o logOptions) body(body []byte) string {
+	if o.maxBodyBytes == 0 || len(body) == 0 {
+		return ""
+	}
+
+	for _, expr := range o.maskJSONPaths {
+		body = maskJSONPath(body, expr)
+	}
+	return redactBody(body, o.maxBodyBytes)
+}
+
+// logBody returns the logged form of a body peeked up to the size limit. JSON paths cannot be resolved in a
+// truncated document, so truncated bodies are left out when JSON paths are masked.
+func (o logOptions) logBody(body []byte, truncated bool) string {
+	if truncated && len(o.maskJSONPaths) > 0 {
+		return fmt.Sprintf("[body longer than %d bytes not logged as mask_json_paths cannot be applied]", o.maxBodyBytes)
+	}
+	if truncated {
+		return redactBody(body, 0) + "... (truncated)"
+	}
+	return o.body(body)
+}
+
+// maskJSONPath replaces every value found at expr in a JSON document with the masked value.
+func maskJSONPath(body []byte, expr string) []byte {
+	gjsonPath, err := toGJSONPath(expr)
+	if err != nil || !gjson.ValidBytes(body) {
+		return body
+	}
+
+	result := gjson.GetBytes(body, gjsonPath)
+	if !result.Exists() {
+		return body
+	}
+
+	// Wildcard paths return every match with its position, single matches carry their own.
+	type span struct{ start, end int }
+	var spans []span
+	if len(result.Indexes) > 0 {
+		for _, index := range result.Indexes {
+			value := gjson.ParseBytes(body[index:])
+			spans = append(spans, span{index, index + len(value.Raw)})
+		}
+	} else if result.Index > 0 {
+		spans = append(spans, span{result.Index, result.Index + len(result.Raw)})
+	} else {
+		return body
+	}
+
+	masked := append([]byte(nil), body...)
+	for i := len(spans) - 1; i >= 0; i-- {
+		replacement := []byte(fmt.Sprintf("%q", redactedValue))
+		masked = append(masked[:spans[i].start], append(replacement, masked[spans[i].end:]...)...)
+	}
+	return masked
+}
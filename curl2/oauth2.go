@@ -0,0 +1,195 @@
+package curl2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/go-retryablehttp"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	grantTypeClientCredentials = "client_credentials"
+	grantTypePassword          = "password"
+	grantTypeRefreshToken      = "refresh_token"
+
+	clientAuthStyleBasic = "basic"
+	clientAuthStyleBody  = "body"
+)
+
+// oauth2TokenRequest describes a token request to an OAuth2 token endpoint.
+type oauth2TokenRequest struct {
+	TokenURL        string
+	ClientID        string
+	ClientSecret    string
+	ClientAuthStyle string
+	GrantType       string
+	Scopes          []string
+	Audience        string
+	Username        string
+	Password        string
+	RefreshToken    string
+	ExtraParams     map[string]string
+}
+
+// oauth2Token is a successful token endpoint response.
+type oauth2Token struct {
+	AccessToken  string      `json:"access_token"`
+	TokenType    string      `json:"token_type"`
+	ExpiresIn    json.Number `json:"expires_in"`
+	Scope        string      `json:"scope"`
+	RefreshToken string      `json:"refresh_token"`
+
+	// ExpiresAt is computed from ExpiresIn when the response is received. It is zero if the expiry is unknown.
+	ExpiresAt time.Time `json:"-"`
+}
+
+// oauth2ErrorResponse is the error body defined by RFC 6749 section 5.2.
+type oauth2ErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// validate checks that the fields required by the grant type are present.
+func (t oauth2TokenRequest) validate() error {
+	if t.TokenURL == "" {
+		return fmt.Errorf("token_url must be provided")
+	}
+	if t.ClientID == "" {
+		return fmt.Errorf("client_id must be provided")
+	}
+
+	switch t.GrantType {
+	case grantTypeClientCredentials:
+	case grantTypePassword:
+		if t.Username == "" || t.Password == "" {
+			return fmt.Errorf("username and password must be provided for the password grant")
+		}
+	case grantTypeRefreshToken:
+		if t.RefreshToken == "" {
+			return fmt.Errorf("refresh_token must be provided for the refresh_token grant")
+		}
+	default:
+		return fmt.Errorf("grant_type must be one of client_credentials, password or refresh_token, got: %s", t.GrantType)
+	}
+
+	switch t.ClientAuthStyle {
+	case clientAuthStyleBasic, clientAuthStyleBody:
+	default:
+		return fmt.Errorf("client_auth_style must be one of basic or body, got: %s", t.ClientAuthStyle)
+	}
+
+	return nil
+}
+
+// fetchOAuth2Token requests a token from an OAuth2 token endpoint using the form encoding of RFC 6749.
+func fetchOAuth2Token(ctx context.Context, client *HttpClient, tokenRequest oauth2TokenRequest) (*oauth2Token, error) {
+	if err := tokenRequest.validate(); err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	for key, value := range tokenRequest.ExtraParams {
+		form.Set(key, value)
+	}
+	form.Set("grant_type", tokenRequest.GrantType)
+	if len(tokenRequest.Scopes) > 0 {
+		form.Set("scope", strings.Join(tokenRequest.Scopes, " "))
+	}
+	if tokenRequest.Audience != "" {
+		form.Set("audience", tokenRequest.Audience)
+	}
+
+	switch tokenRequest.GrantType {
+	case grantTypePassword:
+		form.Set("username", tokenRequest.Username)
+		form.Set("password", tokenRequest.Password)
+	case grantTypeRefreshToken:
+		form.Set("refresh_token", tokenRequest.RefreshToken)
+	}
+
+	if tokenRequest.ClientAuthStyle == clientAuthStyleBody {
+		form.Set("client_id", tokenRequest.ClientID)
+		if tokenRequest.ClientSecret != "" {
+			form.Set("client_secret", tokenRequest.ClientSecret)
+		}
+	}
+
+	request, err := retryablehttp.NewRequestWithContext(ctx, "POST", tokenRequest.TokenURL, []byte(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create token request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if tokenRequest.ClientAuthStyle == clientAuthStyleBasic {
+		request.SetBasicAuth(url.QueryEscape(tokenRequest.ClientID), url.QueryEscape(tokenRequest.ClientSecret))
+	}
+
+	res, body, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error sending token request: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, tokenError(res.Status, body)
+	}
+
+	return parseTokenResponse(body)
+}
+
+// parseTokenResponse decodes a token response and computes its expiry.
+func parseTokenResponse(body []byte) (*oauth2Token, error) {
+	var token oauth2Token
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("unable to decode token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("token response does not contain an access_token")
+	}
+
+	if token.ExpiresIn != "" {
+		expiresIn, err := token.ExpiresIn.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid expires_in in token response: %w", err)
+		}
+		token.ExpiresAt = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+
+	return &token, nil
+}
+
+// tokenError turns an unsuccessful token response into an error, using the OAuth2 error fields when present.
+func tokenError(status string, body []byte) error {
+	var errorResponse oauth2ErrorResponse
+	if err := json.Unmarshal(body, &errorResponse); err == nil && errorResponse.Error != "" {
+		if errorResponse.ErrorDescription != "" {
+			return fmt.Errorf("token endpoint returned %s: %s: %s", status, errorResponse.Error, errorResponse.ErrorDescription)
+		}
+		return fmt.Errorf("token endpoint returned %s: %s", status, errorResponse.Error)
+	}
+
+	return fmt.Errorf("token endpoint returned %s: %s", status, redactBody(body, defaultSnippetBytes))
+}
+
+// expiresInSeconds returns the seconds left until the token expires, or 0 if unknown. It is computed from
+// ExpiresAt so that cached tokens report their remaining lifetime.
+func (t *oauth2Token) expiresInSeconds() int64 {
+	if t.ExpiresAt.IsZero() {
+		return 0
+	}
+	expiresIn := int64(time.Until(t.ExpiresAt) / time.Second)
+	if expiresIn < 0 {
+		return 0
+	}
+	return expiresIn
+}
+
+// expiresAtString formats the expiry in RFC 3339, or returns an empty string if unknown.
+func (t *oauth2Token) expiresAtString() string {
+	if t.ExpiresAt.IsZero() {
+		return ""
+	}
+	return t.ExpiresAt.UTC().Format(time.RFC3339)
+}
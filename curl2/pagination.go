@@ -0,0 +1,311 @@
+package curl2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/tidwall/gjson"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	paginationLinkHeader = "link_header"
+	paginationNextURL    = "next_url"
+	paginationCursor     = "cursor"
+	paginationPage       = "page"
+	paginationOffset     = "offset"
+
+	defaultMaxPages = 100
+)
+
+// paginationModel maps the `pagination` attribute of the curl2 data source.
+type paginationModel struct {
+	Strategy      types.String `tfsdk:"strategy"`
+	NextURLPath   types.String `tfsdk:"next_url_path"`
+	CursorPath    types.String `tfsdk:"cursor_path"`
+	CursorParam   types.String `tfsdk:"cursor_param"`
+	PageParam     types.String `tfsdk:"page_param"`
+	StartPage     types.Int64  `tfsdk:"start_page"`
+	OffsetParam   types.String `tfsdk:"offset_param"`
+	PageSizeParam types.String `tfsdk:"page_size_param"`
+	PageSize      types.Int64  `tfsdk:"page_size"`
+	ItemsPath     types.String `tfsdk:"items_path"`
+	MaxPages      types.Int64  `tfsdk:"max_pages"`
+}
+
+// paginationConfig is the resolved form of paginationModel with defaults applied.
+type paginationConfig struct {
+	strategy      string
+	nextURLPath   string
+	cursorPath    string
+	cursorParam   string
+	pageParam     string
+	startPage     int64
+	offsetParam   string
+	pageSizeParam string
+	pageSize      int64
+	itemsPath     string
+	maxPages      int64
+}
+
+// paginationState tracks the position of the next page request.
+type paginationState struct {
+	page   int64
+	offset int64
+	seen   map[string]bool
+	// origin is the URL of the first page, the only scheme and host next page links may point to.
+	origin *url.URL
+}
+
+// paginationConfigFromObject decodes and validates a `pagination` attribute. It returns nil when it is not set.
+func paginationConfigFromObject(ctx context.Context, object types.Object) (*paginationConfig, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if object.IsNull() || object.IsUnknown() {
+		return nil, diags
+	}
+
+	var m paginationModel
+	diags.Append(object.As(ctx, &m, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	config := &paginationConfig{
+		strategy:      m.Strategy.ValueString(),
+		nextURLPath:   m.NextURLPath.ValueString(),
+		cursorPath:    m.CursorPath.ValueString(),
+		cursorParam:   stringOrDefault(m.CursorParam, "cursor"),
+		pageParam:     stringOrDefault(m.PageParam, "page"),
+		startPage:     1,
+		offsetParam:   stringOrDefault(m.OffsetParam, "offset"),
+		pageSizeParam: m.PageSizeParam.ValueString(),
+		pageSize:      m.PageSize.ValueInt64(),
+		itemsPath:     m.ItemsPath.ValueString(),
+		maxPages:      defaultMaxPages,
+	}
+	if !m.StartPage.IsNull() && !m.StartPage.IsUnknown() {
+		config.startPage = m.StartPage.ValueInt64()
+	}
+	if !m.MaxPages.IsNull() && !m.MaxPages.IsUnknown() {
+		config.maxPages = m.MaxPages.ValueInt64()
+	}
+	if config.strategy == paginationOffset && config.pageSizeParam == "" {
+		config.pageSizeParam = "limit"
+	}
+
+	if err := config.validate(); err != nil {
+		diags.AddAttributeError(
+			path.Root("pagination"),
+			"Invalid Pagination Configuration",
+			err.Error(),
+		)
+		return nil, diags
+	}
+
+	return config, diags
+}
+
+func (p *paginationConfig) validate() error {
+	switch p.strategy {
+	case paginationLinkHeader:
+	case paginationNextURL:
+		if p.nextURLPath == "" {
+			return fmt.Errorf("next_url_path must be set for the next_url strategy")
+		}
+	case paginationCursor:
+		if p.cursorPath == "" {
+			return fmt.Errorf("cursor_path must be set for the cursor strategy")
+		}
+	case paginationPage, paginationOffset:
+		if p.itemsPath == "" {
+			return fmt.Errorf("items_path must be set for the %s strategy, as an empty page ends the pagination", p.strategy)
+		}
+	default:
+		return fmt.Errorf("strategy must be one of link_header, next_url, cursor, page or offset, got: %s", p.strategy)
+	}
+
+	if p.maxPages < 1 {
+		return fmt.Errorf("max_pages must be at least 1")
+	}
+	if p.pageSize < 0 {
+		return fmt.Errorf("page_size cannot be negative")
+	}
+	return nil
+}
+
+// firstURL adds the parameters of the first page to uri for the page and offset strategies and records the origin
+// next page links must stay on.
+func (p *paginationConfig) firstURL(uri string, state *paginationState) (string, error) {
+	origin, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	state.page = p.startPage
+	state.seen = map[string]bool{}
+	state.origin = origin
+
+	switch p.strategy {
+	case paginationPage:
+		return p.withPosition(uri, p.pageParam, state.page)
+	case paginationOffset:
+		return p.withPosition(uri, p.offsetParam, 0)
+	}
+	return uri, nil
+}
+
+// nextURL returns the URL of the page following the response of current, or an empty string when it was the last
+// page. itemCount is the number of items found on the current page.
+func (p *paginationConfig) nextURL(current string, r *http.Response, body []byte, itemCount int, state *paginationState) (string, error) {
+	state.seen[current] = true
+
+	var next string
+	switch p.strategy {
+	case paginationLinkHeader:
+		link := nextLink(r.Header.Values("Link"))
+		if link == "" {
+			return "", nil
+		}
+		resolved, err := resolveNextURL(r.Request.URL, state.origin, link)
+		if err != nil {
+			return "", err
+		}
+		next = resolved
+	case paginationNextURL:
+		result, found, err := lookupJSON(body, p.nextURLPath)
+		if err != nil {
+			return "", err
+		}
+		if !found || result.String() == "" {
+			return "", nil
+		}
+		resolved, err := resolveNextURL(r.Request.URL, state.origin, result.String())
+		if err != nil {
+			return "", err
+		}
+		next = resolved
+	case paginationCursor:
+		result, found, err := lookupJSON(body, p.cursorPath)
+		if err != nil {
+			return "", err
+		}
+		if !found || result.Type == gjson.Null || result.String() == "" {
+			return "", nil
+		}
+		withCursor, err := setQueryParam(current, p.cursorParam, result.String())
+		if err != nil {
+			return "", err
+		}
+		next = withCursor
+	case paginationPage, paginationOffset:
+		if itemCount == 0 || (p.pageSize > 0 && int64(itemCount) < p.pageSize) {
+			return "", nil
+		}
+		var err error
+		if p.strategy == paginationPage {
+			state.page++
+			next, err = p.withPosition(current, p.pageParam, state.page)
+		} else {
+			state.offset += int64(itemCount)
+			next, err = p.withPosition(current, p.offsetParam, state.offset)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// A server handing out the same link again would otherwise be paged until max_pages.
+	if state.seen[next] {
+		return "", nil
+	}
+	return next, nil
+}
+
+// withPosition sets the page or offset parameter, along with the page size when configured.
+func (p *paginationConfig) withPosition(uri string, param string, position int64) (string, error) {
+	uri, err := setQueryParam(uri, param, strconv.FormatInt(position, 10))
+	if err != nil {
+		return "", err
+	}
+	if p.pageSizeParam != "" && p.pageSize > 0 {
+		return setQueryParam(uri, p.pageSizeParam, strconv.FormatInt(p.pageSize, 10))
+	}
+	return uri, nil
+}
+
+// pageItems returns the raw JSON items found at the items path of a page.
+func (p *paginationConfig) pageItems(body []byte) ([]json.RawMessage, error) {
+	if p.itemsPath == "" {
+		return nil, nil
+	}
+
+	result, found, err := lookupJSON(body, p.itemsPath)
+	if err != nil {
+		return nil, err
+	}
+	if !found || result.Type == gjson.Null {
+		return nil, nil
+	}
+	if !result.IsArray() {
+		return nil, fmt.Errorf("items_path %q does not point to an array", p.itemsPath)
+	}
+
+	var items []json.RawMessage
+	for _, item := range result.Array() {
+		items = append(items, json.RawMessage(item.Raw))
+	}
+	return items, nil
+}
+
+var linkPattern = regexp.MustCompile(`<([^>]*)>\s*((?:;\s*[^;,]+)*)`)
+var linkRelPattern = regexp.MustCompile(`(?i);\s*rel\s*=\s*"?([^";]+)"?`)
+
+// nextLink returns the target of the RFC 5988 link with relation type "next".
+func nextLink(headers []string) string {
+	for _, header := range headers {
+		for _, match := range linkPattern.FindAllStringSubmatch(header, -1) {
+			rel := linkRelPattern.FindStringSubmatch(match[2])
+			if rel == nil {
+				continue
+			}
+			for _, relType := range strings.Fields(rel[1]) {
+				if strings.EqualFold(relType, "next") {
+					return match[1]
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// resolveNextURL resolves a next page link against the URL the page was served from after redirects. Links to
+// another scheme or host than origin are refused, as the request credentials would be sent along.
+func resolveNextURL(base *url.URL, origin *url.URL, reference string) (string, error) {
+	referenceURL, err := url.Parse(reference)
+	if err != nil {
+		return "", fmt.Errorf("invalid next page URL %q: %w", reference, err)
+	}
+	next := base.ResolveReference(referenceURL)
+	if !strings.EqualFold(next.Scheme, origin.Scheme) || !strings.EqualFold(next.Host, origin.Host) {
+		return "", fmt.Errorf("next page URL %s is not on %s://%s, the scheme and host of the request", redactURL(next), origin.Scheme, origin.Host)
+	}
+	return next.String(), nil
+}
+
+func setQueryParam(uri string, name string, value string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set(name, value)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
@@ -3,17 +3,22 @@ package curl2
 import (
 	"context"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"os"
+	"time"
 )
 
 var (
-	_ provider.Provider = &curl2Provider{}
+	_ provider.Provider                       = &curl2Provider{}
+	_ provider.ProviderWithEphemeralResources = &curl2Provider{}
+	_ provider.ProviderWithFunctions          = &curl2Provider{}
 )
 
 func NewProvider() provider.Provider {
@@ -26,21 +31,58 @@ type curl2Provider struct{}
 type curl2ProviderModel struct {
 	DisableTLS types.Bool   `tfsdk:"disable_tls"`
 	TimeoutMS  types.Int64  `tfsdk:"timeout_ms"`
+	HARFile    types.String `tfsdk:"har_file"`
 	Retry      types.Object `tfsdk:"retry"`
+	TLS        types.Object `tfsdk:"tls"`
 	AzureAD    types.Object `tfsdk:"azure_ad"`
 	Auth0      types.Object `tfsdk:"auth0"`
+	OAuth2     types.Object `tfsdk:"oauth2"`
+	TokenCache types.Object `tfsdk:"token_cache"`
+	Logging    types.Object `tfsdk:"logging"`
 }
 
 type retryModel struct {
-	RetryAttempts types.Int64 `tfsdk:"retry_attempts"`
-	MinDelay      types.Int64 `tfsdk:"min_delay_ms"`
-	MaxDelay      types.Int64 `tfsdk:"max_delay_ms"`
+	RetryAttempts        types.Int64  `tfsdk:"retry_attempts"`
+	MinDelay             types.Int64  `tfsdk:"min_delay_ms"`
+	MaxDelay             types.Int64  `tfsdk:"max_delay_ms"`
+	RetryableStatusCodes types.List   `tfsdk:"retryable_status_codes"`
+	RespectRetryAfter    types.Bool   `tfsdk:"respect_retry_after"`
+	Backoff              types.String `tfsdk:"backoff"`
+	Jitter               types.Bool   `tfsdk:"jitter"`
+	IdempotentOnly       types.Bool   `tfsdk:"idempotent_only"`
 }
 
 type azureADModel struct {
-	ClientID     types.String `tfsdk:"client_id"`
-	ClientSecret types.String `tfsdk:"client_secret"`
-	TenantID     types.String `tfsdk:"tenant_id"`
+	CredentialType            types.String `tfsdk:"credential_type"`
+	ClientID                  types.String `tfsdk:"client_id"`
+	ClientSecret              types.String `tfsdk:"client_secret"`
+	TenantID                  types.String `tfsdk:"tenant_id"`
+	ClientCertificatePath     types.String `tfsdk:"client_certificate_path"`
+	ClientCertificatePassword types.String `tfsdk:"client_certificate_password"`
+	FederatedTokenFile        types.String `tfsdk:"federated_token_file"`
+	MSIEndpoint               types.String `tfsdk:"msi_endpoint"`
+	AuthorityHost             types.String `tfsdk:"authority_host"`
+}
+
+type oauth2Model struct {
+	TokenURL        types.String `tfsdk:"token_url"`
+	ClientID        types.String `tfsdk:"client_id"`
+	ClientSecret    types.String `tfsdk:"client_secret"`
+	ClientAuthStyle types.String `tfsdk:"client_auth_style"`
+	Scopes          types.List   `tfsdk:"scopes"`
+	Audience        types.String `tfsdk:"audience"`
+	ExtraParams     types.Map    `tfsdk:"extra_params"`
+}
+
+type tokenCacheModel struct {
+	Enabled            types.Bool  `tfsdk:"enabled"`
+	MinValiditySeconds types.Int64 `tfsdk:"min_validity_seconds"`
+}
+
+type loggingModel struct {
+	MaskHeaders   types.List  `tfsdk:"mask_headers"`
+	MaskJSONPaths types.List  `tfsdk:"mask_json_paths"`
+	MaxBodyBytes  types.Int64 `tfsdk:"max_body_bytes"`
 }
 
 type auth0Model struct {
@@ -64,7 +106,11 @@ func (c *curl2Provider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 			},
 			"timeout_ms": schema.Int64Attribute{
 				Optional:    true,
-				Description: "Request Timeout in milliseconds. Defaults to 0, no timeout",
+				Description: "Request Timeout in milliseconds, applied to each attempt. Defaults to 0, no timeout",
+			},
+			"har_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path of an HTTP Archive (HAR) file recording every request made by the provider, including the token requests, with timings and retry attempts. Secrets are masked as in the logs and bodies follow the `logging` settings. Entries are appended to an existing archive, delete the file to start a new one. You can also set it as ENV variable `CURL2_HAR_FILE`",
 			},
 		},
 		Blocks: map[string]schema.Block{
@@ -83,13 +129,42 @@ func (c *curl2Provider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 						Description: "The maximum delay between retry requests in milliseconds.",
 						Optional:    true,
 					},
+					"retryable_status_codes": schema.ListAttribute{
+						Description: "Response status codes that trigger a retry. Defaults to 429 and all 5xx codes except 501. Connection errors are always retried.",
+						ElementType: types.Int64Type,
+						Optional:    true,
+					},
+					"respect_retry_after": schema.BoolAttribute{
+						Description: "Wait for the duration given in the `Retry-After` header of 429 and 503 responses instead of the computed backoff, up to `max_delay_ms`. Defaults to true.",
+						Optional:    true,
+					},
+					"backoff": schema.StringAttribute{
+						Description: "Backoff strategy between retries, `exponential` or `linear`. Defaults to `exponential`.",
+						Optional:    true,
+					},
+					"jitter": schema.BoolAttribute{
+						Description: "Randomise the delay between the minimum delay and the computed backoff. Defaults to false.",
+						Optional:    true,
+					},
+					"idempotent_only": schema.BoolAttribute{
+						Description: "Only retry idempotent requests, so POST and PATCH requests are never retried. Defaults to false.",
+						Optional:    true,
+					},
 				},
 			},
+			"tls": schema.SingleNestedBlock{
+				Description: "TLS configuration for custom CA trust and client certificates (mutual TLS). Can be overridden per `curl2` data source.",
+				Attributes:  tlsProviderAttributes(),
+			},
 			"azure_ad": schema.SingleNestedBlock{
 				Description: "Azure AD Configuration which is required if you are using `curl2_azuread_token` data",
 				Attributes: map[string]schema.Attribute{
+					"credential_type": schema.StringAttribute{
+						Description: "Credential used to authenticate, one of `ClientSecret`, `ClientCertificate`, `WorkloadIdentity` or `ManagedIdentity`. Defaults to `ClientSecret`. You can also set it as ENV variable `AZURE_CREDENTIAL_TYPE`",
+						Optional:    true,
+					},
 					"client_id": schema.StringAttribute{
-						Description: "Application ID of an Azure service principal. You can also set it as ENV variable `AZURE_CLIENT_ID`",
+						Description: "Application ID of an Azure service principal, or the client ID of a user assigned managed identity. You can also set it as ENV variable `AZURE_CLIENT_ID`",
 						Optional:    true,
 					},
 					"client_secret": schema.StringAttribute{
@@ -100,6 +175,27 @@ func (c *curl2Provider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 						Description: "ID of the application's Azure AD tenant. You can also set it as ENV variable `AZURE_TENANT_ID`",
 						Optional:    true,
 					},
+					"client_certificate_path": schema.StringAttribute{
+						Description: "Path to a PEM or PFX file with the certificate and private key of the service principal, used by `ClientCertificate`. You can also set it as ENV variable `AZURE_CLIENT_CERTIFICATE_PATH`",
+						Optional:    true,
+					},
+					"client_certificate_password": schema.StringAttribute{
+						Description: "Password of the client certificate file. You can also set it as ENV variable `AZURE_CLIENT_CERTIFICATE_PASSWORD`",
+						Optional:    true,
+						Sensitive:   true,
+					},
+					"federated_token_file": schema.StringAttribute{
+						Description: "Path to the federated token file, used by `WorkloadIdentity`. You can also set it as ENV variable `AZURE_FEDERATED_TOKEN_FILE`",
+						Optional:    true,
+					},
+					"msi_endpoint": schema.StringAttribute{
+						Description: "Token endpoint of the instance metadata service, used by `ManagedIdentity`. Defaults to `http://169.254.169.254/metadata/identity/oauth2/token`. You can also set it as ENV variable `AZURE_MSI_ENDPOINT`",
+						Optional:    true,
+					},
+					"authority_host": schema.StringAttribute{
+						Description: "Azure AD authority host for sovereign clouds, for example `https://login.microsoftonline.us/`. Defaults to the Azure public cloud. You can also set it as ENV variable `AZURE_AUTHORITY_HOST`",
+						Optional:    true,
+					},
 				},
 			},
 			"auth0": schema.SingleNestedBlock{
@@ -119,10 +215,98 @@ func (c *curl2Provider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 					},
 				},
 			},
+			"token_cache": schema.SingleNestedBlock{
+				Description: "Cache of the Azure AD, Auth0 and OAuth2 tokens requested by this provider, so repeated reads with the same credentials and scopes or audience reuse a token. Enabled by default.",
+				Attributes: map[string]schema.Attribute{
+					"enabled": schema.BoolAttribute{
+						Description: "Whether tokens are cached. Defaults to true.",
+						Optional:    true,
+					},
+					"min_validity_seconds": schema.Int64Attribute{
+						Description: "A cached token is replaced once it expires within this many seconds. Tokens issued for a shorter time are reused until they expire. Defaults to 300.",
+						Optional:    true,
+					},
+				},
+			},
+			"logging": schema.SingleNestedBlock{
+				Description: "Logging of the HTTP traffic, written to the `http` subsystem at DEBUG for requests and responses and TRACE for their headers and bodies. Set its level with the ENV variable `TF_LOG_PROVIDER_CURL2_HTTP`. Authorization, cookie, token, secret, password, API key and signature headers and JSON fields are always masked.",
+				Attributes: map[string]schema.Attribute{
+					"mask_headers": schema.ListAttribute{
+						Description: "Additional header names to mask, case-insensitive. `*` matches any characters, for example `X-Internal-*`.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+					"mask_json_paths": schema.ListAttribute{
+						Description: "JSONPath expressions like `$.data.ssn` or gjson paths like `users.#.email` of JSON body values to mask.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+					"max_body_bytes": schema.Int64Attribute{
+						Description: "Logged bodies are truncated to this many bytes. Bodies are only read for TRACE logs, and longer bodies are left out when `mask_json_paths` is set. 0 leaves bodies out of the logs. Defaults to 4096.",
+						Optional:    true,
+					},
+				},
+			},
+			"oauth2": schema.SingleNestedBlock{
+				Description: "OAuth2 client credentials configuration which is required if you are using `auth_type = \"OAuth2\"` on `curl2` data",
+				Attributes: map[string]schema.Attribute{
+					"token_url": schema.StringAttribute{
+						Description: "Token endpoint URL.",
+						Optional:    true,
+					},
+					"client_id": schema.StringAttribute{
+						Description: "Client ID of the application.",
+						Optional:    true,
+					},
+					"client_secret": schema.StringAttribute{
+						Description: "Client secret of the application.",
+						Optional:    true,
+						Sensitive:   true,
+					},
+					"client_auth_style": schema.StringAttribute{
+						Description: "How the client credentials are sent, `basic` for an HTTP Basic Authorization header or `body` for form parameters. Defaults to `basic`.",
+						Optional:    true,
+					},
+					"scopes": schema.ListAttribute{
+						Description: "Default scopes to request, replaced by `token_scopes` of the request.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+					"audience": schema.StringAttribute{
+						Description: "Default audience to request, replaced by `token_audience` of the request.",
+						Optional:    true,
+					},
+					"extra_params": schema.MapAttribute{
+						Description: "Additional form parameters sent to the token endpoint.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
+			},
 		},
 	}
 }
 
+func tlsProviderAttributes() map[string]schema.Attribute {
+	attributes := map[string]schema.Attribute{}
+	for name, description := range tlsAttributeDescriptions {
+		if name == "cipher_suites" {
+			attributes[name] = schema.ListAttribute{
+				Description: description,
+				ElementType: types.StringType,
+				Optional:    true,
+			}
+			continue
+		}
+		attributes[name] = schema.StringAttribute{
+			Description: description,
+			Optional:    true,
+			Sensitive:   tlsSensitiveAttributes[name],
+		}
+	}
+	return attributes
+}
+
 func (c *curl2Provider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	tflog.Info(ctx, "Configuring curl2 client", map[string]any{"success": false})
 	// Retrieve provider data from configuration
@@ -140,63 +324,67 @@ func (c *curl2Provider) Configure(ctx context.Context, req provider.ConfigureReq
 		if resp.Diagnostics.HasError() {
 			return
 		}
+	}
 
-		err := os.Setenv("AZURE_CLIENT_ID", azureAD.ClientID.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Unable to set AZURE_CLIENT_ID Env Variables",
-				err.Error(),
-			)
+	var auth0 auth0Model
+	if !config.Auth0.IsNull() && !config.Auth0.IsUnknown() {
+		diags = config.Auth0.As(ctx, &auth0, basetypes.ObjectAsOptions{})
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
 			return
 		}
-		err = os.Setenv("AZURE_CLIENT_SECRET", azureAD.ClientSecret.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Unable to set AZURE_CLIENT_SECRET Env Variables",
-				err.Error(),
-			)
+	}
+
+	var tokenCacheConfig tokenCacheModel
+	if !config.TokenCache.IsNull() && !config.TokenCache.IsUnknown() {
+		diags = config.TokenCache.As(ctx, &tokenCacheConfig, basetypes.ObjectAsOptions{})
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
 			return
 		}
-		err = os.Setenv("AZURE_TENANT_ID", azureAD.TenantID.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Unable to set AZURE_TENANT_ID Env Variables",
-				err.Error(),
+	}
+
+	minValidity := defaultTokenMinValidity
+	if !tokenCacheConfig.MinValiditySeconds.IsNull() && !tokenCacheConfig.MinValiditySeconds.IsUnknown() {
+		if tokenCacheConfig.MinValiditySeconds.ValueInt64() < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("token_cache").AtName("min_validity_seconds"),
+				"Invalid Token Cache Min Validity",
+				"Min validity cannot be negative",
 			)
 			return
 		}
+		minValidity = time.Duration(tokenCacheConfig.MinValiditySeconds.ValueInt64()) * time.Second
 	}
+	tokenCacheDisabled := !tokenCacheConfig.Enabled.IsNull() && !tokenCacheConfig.Enabled.ValueBool()
 
-	var auth0Config auth0Model
-	if !config.Auth0.IsNull() && !config.Auth0.IsUnknown() {
-		diags = config.Auth0.As(ctx, &auth0Config, basetypes.ObjectAsOptions{})
+	var oauth2TokenConfig *oauth2TokenRequest
+	if !config.OAuth2.IsNull() && !config.OAuth2.IsUnknown() {
+		var oauth2Config oauth2Model
+		diags = config.OAuth2.As(ctx, &oauth2Config, basetypes.ObjectAsOptions{})
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
 			return
 		}
 
-		err := os.Setenv("AUTH0_CLIENT_ID", auth0Config.ClientID.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Unable to set AUTH0_CLIENT_ID Env Variables",
-				err.Error(),
-			)
-			return
+		oauth2TokenConfig = &oauth2TokenRequest{
+			TokenURL:        oauth2Config.TokenURL.ValueString(),
+			ClientID:        oauth2Config.ClientID.ValueString(),
+			ClientSecret:    oauth2Config.ClientSecret.ValueString(),
+			ClientAuthStyle: clientAuthStyleBasic,
+			GrantType:       grantTypeClientCredentials,
+			Audience:        oauth2Config.Audience.ValueString(),
 		}
-		err = os.Setenv("AUTH0_CLIENT_SECRET", auth0Config.ClientSecret.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Unable to set AUTH0_CLIENT_SECRET Env Variables",
-				err.Error(),
-			)
-			return
+		if oauth2Config.ClientAuthStyle.ValueString() != "" {
+			oauth2TokenConfig.ClientAuthStyle = oauth2Config.ClientAuthStyle.ValueString()
 		}
-		err = os.Setenv("AUTH0_DOMAIN", auth0Config.Domain.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Unable to set AUTH0_DOMAIN Env Variables",
-				err.Error(),
-			)
+		if !oauth2Config.Scopes.IsNull() && !oauth2Config.Scopes.IsUnknown() {
+			resp.Diagnostics.Append(oauth2Config.Scopes.ElementsAs(ctx, &oauth2TokenConfig.Scopes, false)...)
+		}
+		if !oauth2Config.ExtraParams.IsNull() && !oauth2Config.ExtraParams.IsUnknown() {
+			resp.Diagnostics.Append(oauth2Config.ExtraParams.ElementsAs(ctx, &oauth2TokenConfig.ExtraParams, false)...)
+		}
+		if resp.Diagnostics.HasError() {
 			return
 		}
 	}
@@ -210,14 +398,87 @@ func (c *curl2Provider) Configure(ctx context.Context, req provider.ConfigureReq
 		}
 	}
 
+	var retryableStatusCodes []int
+	if !retry.RetryableStatusCodes.IsNull() && !retry.RetryableStatusCodes.IsUnknown() {
+		diags = retry.RetryableStatusCodes.ElementsAs(ctx, &retryableStatusCodes, false)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	backoff := backoffExponential
+	if retry.Backoff.ValueString() != "" {
+		backoff = retry.Backoff.ValueString()
+	}
+	if backoff != backoffExponential && backoff != backoffLinear {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("retry").AtName("backoff"),
+			"Invalid Retry Backoff",
+			"Backoff must be one of exponential or linear, got: "+backoff,
+		)
+		return
+	}
+
+	respectRetryAfter := true
+	if !retry.RespectRetryAfter.IsNull() && !retry.RespectRetryAfter.IsUnknown() {
+		respectRetryAfter = retry.RespectRetryAfter.ValueBool()
+	}
+
+	logOpts, diags := logOptionsFromObject(ctx, config.Logging)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var har *harRecorder
+	if harFile := valueOrEnv(config.HARFile, harFileEnv); harFile != "" {
+		var err error
+		har, err = openHARRecorder(harFile)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("har_file"),
+				"Invalid HAR File",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	tlsOpts, diags := tlsOptionsFromObject(ctx, config.TLS, path.Root("tls"))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	opts := ApiClientOpts{
-		insecure: config.DisableTLS.ValueBool(),
-		timeout:  config.TimeoutMS.ValueInt64(),
+		insecure:             config.DisableTLS.ValueBool(),
+		timeout:              config.TimeoutMS.ValueInt64(),
+		maxRetries:           int(retry.RetryAttempts.ValueInt64()),
+		minDelay:             retry.MinDelay,
+		maxDelay:             retry.MaxDelay,
+		retryableStatusCodes: retryableStatusCodes,
+		respectRetryAfter:    respectRetryAfter,
+		backoff:              backoff,
+		jitter:               retry.Jitter.ValueBool(),
+		idempotentOnly:       retry.IdempotentOnly.ValueBool(),
+		logging:              logOpts,
+		har:                  har,
+	}
+	if tlsOpts != nil {
+		opts.tls = *tlsOpts
+	}
+	providerData := &curl2ProviderData{
+		client:  NewClient(opts),
+		azureAD: newAzureADConfig(azureAD),
+		auth0:   newAuth0Config(auth0),
+		oauth2:  oauth2TokenConfig,
+		tokens:  newTokenCache(tokenCacheDisabled, minValidity),
 	}
-	client := NewClient(opts)
 
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
+	resp.EphemeralResourceData = providerData
 
 	tflog.Info(ctx, "Configured curl2 client", map[string]any{"success": true})
 }
@@ -227,9 +488,32 @@ func (c *curl2Provider) DataSources(ctx context.Context) []func() datasource.Dat
 		NewCurl2DataSource,
 		NewAzureADTokenDataSource,
 		NewAuth0TokenDataSource,
+		NewOAuth2TokenDataSource,
+		NewCurl2RequestsDataSource,
+		NewCurl2GraphQLDataSource,
 	}
 }
 
 func (c *curl2Provider) Resources(ctx context.Context) []func() resource.Resource {
-	return []func() resource.Resource{}
+	return []func() resource.Resource{
+		NewCurl2RequestResource,
+	}
+}
+
+func (c *curl2Provider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewCurl2EphemeralResource,
+		NewAzureADTokenEphemeralResource,
+		NewAuth0TokenEphemeralResource,
+	}
+}
+
+func (c *curl2Provider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewJSONPathFunction,
+		NewBasicAuthHeaderFunction,
+		NewURLBuildFunction,
+		NewJWTDecodeFunction,
+		NewHeaderGetFunction,
+	}
 }
@@ -0,0 +1,131 @@
+package curl2
+
+import (
+	"context"
+	"fmt"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"os"
+	"time"
+)
+
+const (
+	authTypeBearer  = "Bearer"
+	authTypeBasic   = "Basic"
+	authTypeAzureAD = "AzureAD"
+	authTypeAuth0   = "Auth0"
+	authTypeOAuth2  = "OAuth2"
+	authTypeAWSv4   = "AWSv4"
+	authTypeHMAC    = "HMAC"
+)
+
+// curl2ProviderData is handed to every data source and resource through Configure. Each provider alias has its
+// own, so credentials of different aliases never mix.
+type curl2ProviderData struct {
+	client *HttpClient
+
+	// azureAD and auth0 are resolved from the provider blocks and their environment variables.
+	azureAD azureADConfig
+	auth0   auth0Config
+
+	// oauth2 is the client credentials configuration of the provider oauth2 block, nil if the block is not set.
+	oauth2 *oauth2TokenRequest
+
+	tokens *tokenCache
+}
+
+// azureADToken returns a cached Azure AD token for the scopes, requesting a new one when needed.
+func (p *curl2ProviderData) azureADToken(ctx context.Context, scopes []string) (azcore.AccessToken, error) {
+	c := p.azureAD
+	key := tokenCacheKey(append([]string{authTypeAzureAD, c.credentialType(), c.ClientID, c.ClientSecret, c.TenantID,
+		c.ClientCertificatePath, c.ClientCertificatePassword, c.FederatedTokenFile, c.MSIEndpoint, c.AuthorityHost}, scopes...)...)
+
+	return cachedToken(ctx, p.tokens, key, func(ctx context.Context) (azcore.AccessToken, time.Time, error) {
+		token, err := azureADToken(ctx, p.client, c, scopes)
+		return token, token.ExpiresOn, err
+	})
+}
+
+// auth0Token returns a cached Auth0 token for the request, requesting a new one when needed.
+func (p *curl2ProviderData) auth0Token(ctx context.Context, tokenRequest auth0TokenRequest) (*oauth2Token, error) {
+	c := p.auth0
+	parts := []string{authTypeAuth0, c.ClientID, c.ClientSecret, c.Domain, tokenRequest.Audience, tokenRequest.Organization}
+	for _, name := range sortedKeys(tokenRequest.ExtraParams) {
+		parts = append(parts, name, tokenRequest.ExtraParams[name])
+	}
+
+	return cachedToken(ctx, p.tokens, tokenCacheKey(parts...), func(ctx context.Context) (*oauth2Token, time.Time, error) {
+		token, err := auth0Token(ctx, p.client, c, tokenRequest)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		return token, token.ExpiresAt, nil
+	})
+}
+
+// oauth2Token returns a cached token for the request of the provider oauth2 block or of the oauth2 token data
+// source, requesting a new one when needed.
+func (p *curl2ProviderData) oauth2Token(ctx context.Context, tokenRequest oauth2TokenRequest) (*oauth2Token, error) {
+	parts := []string{authTypeOAuth2, tokenRequest.TokenURL, tokenRequest.ClientID, tokenRequest.ClientSecret,
+		tokenRequest.ClientAuthStyle, tokenRequest.GrantType, tokenRequest.Audience, tokenRequest.Username,
+		tokenRequest.Password, tokenRequest.RefreshToken}
+	parts = append(parts, tokenRequest.Scopes...)
+	for _, name := range sortedKeys(tokenRequest.ExtraParams) {
+		parts = append(parts, name, tokenRequest.ExtraParams[name])
+	}
+
+	return cachedToken(ctx, p.tokens, tokenCacheKey(parts...), func(ctx context.Context) (*oauth2Token, time.Time, error) {
+		token, err := fetchOAuth2Token(ctx, p.client, tokenRequest)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		return token, token.ExpiresAt, nil
+	})
+}
+
+// bearerToken fetches a token for the given identity provider auth type using the provider configuration.
+// Scopes apply to AzureAD and OAuth2, the audience to Auth0 and OAuth2.
+func (p *curl2ProviderData) bearerToken(ctx context.Context, authType string, scopes []string, audience string) (string, error) {
+	switch authType {
+	case authTypeAzureAD:
+		token, err := p.azureADToken(ctx, scopes)
+		if err != nil {
+			return "", err
+		}
+		return token.Token, nil
+	case authTypeAuth0:
+		token, err := p.auth0Token(ctx, auth0TokenRequest{Audience: audience})
+		if err != nil {
+			return "", err
+		}
+		return token.AccessToken, nil
+	case authTypeOAuth2:
+		if p.oauth2 == nil {
+			return "", fmt.Errorf("the provider oauth2 block must be configured to use the OAuth2 auth type")
+		}
+
+		tokenRequest := *p.oauth2
+		if len(scopes) > 0 {
+			tokenRequest.Scopes = scopes
+		}
+		if audience != "" {
+			tokenRequest.Audience = audience
+		}
+
+		token, err := p.oauth2Token(ctx, tokenRequest)
+		if err != nil {
+			return "", err
+		}
+		return token.AccessToken, nil
+	default:
+		return "", fmt.Errorf("auth type %s does not fetch tokens", authType)
+	}
+}
+
+// valueOrEnv returns the configured value, or the environment variable when the value is not set.
+func valueOrEnv(value types.String, env string) string {
+	if value.ValueString() != "" {
+		return value.ValueString()
+	}
+	return os.Getenv(env)
+}
@@ -0,0 +1,91 @@
+package curl2
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+const (
+	redactedValue       = "***"
+	defaultSnippetBytes = 1024
+)
+
+// sensitiveKeyPattern matches field, parameter and header names whose values must never be shown.
+var sensitiveKeyPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|api[_-]?key|authorization|cookie|credential|private[_-]?key|assertion|signature)`)
+
+// sensitivePairPattern matches key=value and key: value pairs with a sensitive key in non JSON text.
+var sensitivePairPattern = regexp.MustCompile(`(?i)([\w.-]*(?:password|passwd|secret|token|api[_-]?key|credential|assertion|signature)[\w.-]*"?\s*[=:]\s*"?)([^&\s,;"]+)`)
+
+// redactBody masks sensitive values in a JSON or form/text body and truncates it to limit bytes.
+func redactBody(body []byte, limit int) string {
+	var redacted string
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err == nil {
+		masked, err := json.Marshal(redactJSON(data))
+		if err != nil {
+			redacted = string(body)
+		} else {
+			redacted = string(masked)
+		}
+	} else {
+		redacted = sensitivePairPattern.ReplaceAllString(string(body), "${1}"+redactedValue)
+	}
+
+	return truncate(redacted, limit)
+}
+
+// redactJSON replaces the values of sensitive keys anywhere in a decoded JSON document.
+func redactJSON(data interface{}) interface{} {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		for key, nested := range value {
+			if sensitiveKeyPattern.MatchString(key) {
+				value[key] = redactedValue
+				continue
+			}
+			value[key] = redactJSON(nested)
+		}
+	case []interface{}:
+		for i, nested := range value {
+			value[i] = redactJSON(nested)
+		}
+	}
+	return data
+}
+
+// redactURL masks user info and sensitive query parameters of a URL.
+func redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	masked := *u
+	if masked.User != nil {
+		masked.User = url.User(redactedValue)
+	}
+
+	query := masked.Query()
+	changed := false
+	for key := range query {
+		if sensitiveKeyPattern.MatchString(key) {
+			query.Set(key, redactedValue)
+			changed = true
+		}
+	}
+	if changed {
+		masked.RawQuery = query.Encode()
+	}
+
+	return masked.String()
+}
+
+// truncate shortens s to at most limit bytes, noting how much was cut.
+func truncate(s string, limit int) string {
+	if limit <= 0 || len(s) <= limit {
+		return s
+	}
+	return fmt.Sprintf("%s... (truncated, %d bytes total)", s[:limit], len(s))
+}
@@ -0,0 +1,395 @@
+package curl2
+
+import (
+	"context"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// authConfig holds the authentication settings shared by the curl2 data source and resource.
+type authConfig struct {
+	AuthType          string
+	BearerToken       string
+	BasicAuthUsername string
+	BasicAuthPassword string
+	TokenScopes       []string
+	TokenAudience     string
+	AWSSigV4          types.Object
+	HMAC              types.Object
+}
+
+// responseAttrTypes describes the object stored in the `response` attributes.
+func responseAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"uri":            types.StringType,
+		"body":           types.StringType,
+		"status_code":    types.Int64Type,
+		"status_text":    types.StringType,
+		"headers":        types.MapType{ElemType: types.ListType{ElemType: types.StringType}},
+		"protocol":       types.StringType,
+		"content_length": types.Int64Type,
+		"final_url":      types.StringType,
+		"redirect_chain": types.ListType{ElemType: types.StringType},
+		"extracted":      types.MapType{ElemType: types.StringType},
+	}
+}
+
+// responseValue converts an HTTP response into the `response` object. A nil extracted map is stored as null.
+func responseValue(uri string, r *http.Response, body []byte, extracted map[string]string) (types.Object, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	headerValues := map[string]attr.Value{}
+	for name, values := range r.Header {
+		headerList, listDiags := types.ListValueFrom(context.Background(), types.StringType, values)
+		diags.Append(listDiags...)
+		headerValues[name] = headerList
+	}
+	headers, mapDiags := types.MapValue(types.ListType{ElemType: types.StringType}, headerValues)
+	diags.Append(mapDiags...)
+
+	chain, listDiags := types.ListValueFrom(context.Background(), types.StringType, redirectChain(r))
+	diags.Append(listDiags...)
+
+	extractedValue := types.MapNull(types.StringType)
+	if extracted != nil {
+		var extractedDiags diag.Diagnostics
+		extractedValue, extractedDiags = types.MapValueFrom(context.Background(), types.StringType, extracted)
+		diags.Append(extractedDiags...)
+	}
+	if diags.HasError() {
+		return types.ObjectNull(responseAttrTypes()), diags
+	}
+
+	statusText := strings.TrimSpace(strings.TrimPrefix(r.Status, strconv.Itoa(r.StatusCode)))
+	if statusText == "" {
+		statusText = http.StatusText(r.StatusCode)
+	}
+
+	contentLength := r.ContentLength
+	if contentLength < 0 {
+		contentLength = int64(len(body))
+	}
+
+	response, objectDiags := types.ObjectValue(
+		responseAttrTypes(),
+		map[string]attr.Value{
+			"uri":            types.StringValue(uri),
+			"body":           types.StringValue(string(body)),
+			"status_code":    types.Int64Value(int64(r.StatusCode)),
+			"status_text":    types.StringValue(statusText),
+			"headers":        headers,
+			"protocol":       types.StringValue(r.Proto),
+			"content_length": types.Int64Value(contentLength),
+			"final_url":      types.StringValue(r.Request.URL.String()),
+			"redirect_chain": chain,
+			"extracted":      extractedValue,
+		},
+	)
+	diags.Append(objectDiags...)
+	return response, diags
+}
+
+// redirectChain lists the URLs that were redirected away from, in the order they were requested.
+func redirectChain(r *http.Response) []string {
+	chain := []string{}
+	for via := r.Request.Response; via != nil; via = via.Request.Response {
+		chain = append([]string{via.Request.URL.String()}, chain...)
+	}
+	return chain
+}
+
+// headersFromMap converts a Terraform map of strings into plain header values.
+func headersFromMap(ctx context.Context, headers types.Map) (map[string]string, diag.Diagnostics) {
+	result := map[string]string{}
+	if headers.IsNull() || headers.IsUnknown() {
+		return result, nil
+	}
+	diags := headers.ElementsAs(ctx, &result, false)
+	return result, diags
+}
+
+// setHeaders adds the given headers to the request.
+func setHeaders(req *retryablehttp.Request, headers map[string]string) {
+	for eachHeaderKey, eachHeaderValue := range headers {
+		req.Header.Set(eachHeaderKey, eachHeaderValue)
+	}
+}
+
+// applyAuth sets the authentication header described by auth on the request. Identity provider
+// auth types fetch their token using the provider configuration. AWSv4 and HMAC sign headers
+// already set on the request, so it must be applied last.
+func applyAuth(ctx context.Context, req *retryablehttp.Request, auth authConfig, providerData *curl2ProviderData) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	switch auth.AuthType {
+	case "":
+	case authTypeBearer:
+		if auth.BearerToken == "" {
+			diags.AddError(
+				"Invalid Bearer Token",
+				"Bearer Token Parameter must be provided",
+			)
+			return diags
+		}
+
+		req.Header.Set("Authorization", "Bearer "+auth.BearerToken)
+	case authTypeBasic:
+		if auth.BasicAuthUsername == "" || auth.BasicAuthPassword == "" {
+			diags.AddError(
+				"Invalid Basic Auth Token",
+				"Basic Username and Password Parameters must be provided",
+			)
+			return diags
+		}
+
+		req.SetBasicAuth(auth.BasicAuthUsername, auth.BasicAuthPassword)
+	case authTypeAzureAD, authTypeAuth0, authTypeOAuth2:
+		token, err := providerData.bearerToken(ctx, auth.AuthType, auth.TokenScopes, auth.TokenAudience)
+		if err != nil {
+			diags.AddError(
+				"Error getting "+auth.AuthType+" token",
+				err.Error(),
+			)
+			return diags
+		}
+
+		req.Header.Set("Authorization", "Bearer "+token)
+	case authTypeAWSv4:
+		sigV4, sigV4Diags := sigV4ConfigFromObject(ctx, auth.AWSSigV4)
+		diags.Append(sigV4Diags...)
+		if diags.HasError() {
+			return diags
+		}
+
+		body, err := req.BodyBytes()
+		if err != nil {
+			diags.AddError(
+				"Unable to read request body for signing",
+				err.Error(),
+			)
+			return diags
+		}
+
+		signSigV4(req.Request, body, sigV4, time.Now())
+	case authTypeHMAC:
+		signing, hmacDiags := hmacConfigFromObject(ctx, auth.HMAC)
+		diags.Append(hmacDiags...)
+		if diags.HasError() {
+			return diags
+		}
+
+		body, err := req.BodyBytes()
+		if err != nil {
+			diags.AddError(
+				"Unable to read request body for signing",
+				err.Error(),
+			)
+			return diags
+		}
+
+		sign := func(r *http.Request, now time.Time) error {
+			return signHMAC(r, body, signing, now)
+		}
+		if err = sign(req.Request, time.Now()); err != nil {
+			diags.AddError(
+				"Unable to sign request",
+				err.Error(),
+			)
+			return diags
+		}
+		req.Request = req.Request.WithContext(context.WithValue(req.Context(), requestSignerKey{}, requestSigner(sign)))
+	default:
+		diags.AddError(
+			"Invalid Auth Type",
+			"Auth Type must be one of Bearer, Basic, AzureAD, Auth0, OAuth2, AWSv4 or HMAC, got: "+auth.AuthType,
+		)
+	}
+
+	return diags
+}
+
+// preparedRequest is a curl2 request resolved from its configuration, ready to be sent once or, with pagination
+// and wait_for, several times.
+type preparedRequest struct {
+	config         curl2RequestModel
+	client         *HttpClient
+	providerData   *curl2ProviderData
+	total          time.Duration
+	body           *requestBody
+	headers        map[string]string
+	tokenScopes    []string
+	expectedStatus []string
+}
+
+// prepareRequest validates the expected status codes, applies the timeouts and tls overrides of config to client
+// and encodes the body, headers and token scopes.
+func prepareRequest(ctx context.Context, client *HttpClient, providerData *curl2ProviderData, config curl2RequestModel, overrides requestOverrides) (*preparedRequest, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	request := &preparedRequest{config: config, providerData: providerData}
+
+	if !config.ExpectedStatus.IsNull() && !config.ExpectedStatus.IsUnknown() {
+		diags.Append(config.ExpectedStatus.ElementsAs(ctx, &request.expectedStatus, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		for _, pattern := range request.expectedStatus {
+			if _, _, err := parseStatusPattern(pattern); err != nil {
+				diags.AddAttributeError(
+					path.Root("expected_status_codes"),
+					"Invalid Expected Status Code",
+					err.Error(),
+				)
+				return nil, diags
+			}
+		}
+	}
+
+	if !config.Timeouts.IsNull() && !config.Timeouts.IsUnknown() {
+		var timeouts timeoutsModel
+		diags.Append(config.Timeouts.As(ctx, &timeouts, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		overrides.timeouts = timeouts.requestTimeouts()
+	}
+
+	var tlsDiags diag.Diagnostics
+	overrides.tls, tlsDiags = tlsOptionsFromObject(ctx, config.TLS, path.Root("tls"))
+	diags.Append(tlsDiags...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	request.client = client.WithOverrides(overrides)
+	request.total = overrides.timeouts.total
+
+	var bodyDiags diag.Diagnostics
+	request.body, bodyDiags = buildRequestBody(ctx, requestBodyConfig{
+		JSON:        config.JSON,
+		Form:        config.Form,
+		Multipart:   config.Multipart,
+		RawBody:     config.RawBody,
+		BodyBase64:  config.BodyBase64,
+		ContentType: config.ContentType,
+	})
+	diags.Append(bodyDiags...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	var headerDiags diag.Diagnostics
+	request.headers, headerDiags = headersFromMap(ctx, config.Headers)
+	diags.Append(headerDiags...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	if !config.TokenScopes.IsNull() && !config.TokenScopes.IsUnknown() {
+		diags.Append(config.TokenScopes.ElementsAs(ctx, &request.tokenScopes, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+	}
+
+	return request, diags
+}
+
+// withTimeout bounds ctx by the `timeouts.total_ms` of the request, if any.
+func (p *preparedRequest) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.total <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, p.total)
+}
+
+// send builds, authenticates and sends the request to uri. Pagination sends it once per page.
+func (p *preparedRequest) send(ctx context.Context, uri string) (*http.Response, []byte, diag.Diagnostics) {
+	newReq, diags := p.newRequest(ctx, uri)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+
+	r, responseData, err := p.client.Do(newReq)
+	if err != nil {
+		diags.AddError(
+			"Error calling api",
+			err.Error(),
+		)
+		return nil, nil, diags
+	}
+
+	return r, responseData, diags
+}
+
+// newRequest builds and authenticates the request to uri.
+func (p *preparedRequest) newRequest(ctx context.Context, uri string) (*retryablehttp.Request, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var rawBody interface{}
+	if p.body != nil {
+		rawBody = p.body.data
+	}
+
+	newReq, err := retryablehttp.NewRequestWithContext(ctx, p.config.HTTPMethod.ValueString(), uri, rawBody)
+	if err != nil {
+		diags.AddError(
+			"Unable to create new http request",
+			err.Error(),
+		)
+		return nil, diags
+	}
+
+	if p.body != nil {
+		newReq.Header.Set("Content-Type", p.body.contentType)
+	}
+	setHeaders(newReq, p.headers)
+
+	diags.Append(applyAuth(ctx, newReq, authConfig{
+		AuthType:          p.config.AuthType.ValueString(),
+		BearerToken:       p.config.BearerToken.ValueString(),
+		BasicAuthUsername: p.config.BasicAuthUsername.ValueString(),
+		BasicAuthPassword: p.config.BasicAuthPassword.ValueString(),
+		TokenScopes:       p.tokenScopes,
+		TokenAudience:     p.config.TokenAudience.ValueString(),
+		AWSSigV4:          p.config.AWSSigV4,
+		HMAC:              p.config.HMAC,
+	}, p.providerData)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	return newReq, diags
+}
+
+// unexpectedStatus describes a response whose status does not match `expected_status_codes`. It returns an empty
+// string when the status is expected.
+func (p *preparedRequest) unexpectedStatus(r *http.Response, body []byte) string {
+	if len(p.expectedStatus) == 0 {
+		return ""
+	}
+	if matched, _ := statusMatches(r.StatusCode, p.expectedStatus); matched {
+		return ""
+	}
+	return unexpectedStatusDetail(r, body, p.expectedStatus)
+}
+
+// response extracts the configured values from the body and builds the `response` object.
+func (p *preparedRequest) response(ctx context.Context, r *http.Response, body []byte) (types.Object, diag.Diagnostics) {
+	extracted, diags := extractValues(ctx, p.config.Extract, p.config.ExtractOptional, body)
+	if diags.HasError() {
+		return types.ObjectNull(responseAttrTypes()), diags
+	}
+
+	response, responseDiags := responseValue(p.config.URI.ValueString(), r, body, extracted)
+	diags.Append(responseDiags...)
+	return response, diags
+}
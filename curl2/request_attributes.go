@@ -0,0 +1,224 @@
+package curl2
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// curl2RequestModel holds the attributes shared by the curl2 data source and ephemeral resource, whose models
+// embed it.
+type curl2RequestModel struct {
+	URI               types.String `tfsdk:"uri"`
+	HTTPMethod        types.String `tfsdk:"http_method"`
+	JSON              types.String `tfsdk:"json"`
+	Form              types.Map    `tfsdk:"form"`
+	Multipart         types.Object `tfsdk:"multipart"`
+	RawBody           types.String `tfsdk:"raw_body"`
+	BodyBase64        types.String `tfsdk:"body_base64"`
+	ContentType       types.String `tfsdk:"content_type"`
+	Response          types.Object `tfsdk:"response"`
+	AuthType          types.String `tfsdk:"auth_type"`
+	BearerToken       types.String `tfsdk:"bearer_token"`
+	BasicAuthUsername types.String `tfsdk:"basic_auth_username"`
+	BasicAuthPassword types.String `tfsdk:"basic_auth_password"`
+	TokenScopes       types.List   `tfsdk:"token_scopes"`
+	TokenAudience     types.String `tfsdk:"token_audience"`
+	AWSSigV4          types.Object `tfsdk:"aws_sigv4"`
+	HMAC              types.Object `tfsdk:"hmac"`
+	Headers           types.Map    `tfsdk:"headers"`
+	Timeouts          types.Object `tfsdk:"timeouts"`
+	TLS               types.Object `tfsdk:"tls"`
+	ExpectedStatus    types.List   `tfsdk:"expected_status_codes"`
+	Extract           types.Map    `tfsdk:"extract"`
+	ExtractOptional   types.List   `tfsdk:"extract_optional"`
+}
+
+// attributeKind is the type of an attribute described by an attributeSpec.
+type attributeKind int
+
+const (
+	stringAttribute attributeKind = iota
+	int64Attribute
+	stringListAttribute
+	stringMapAttribute
+	nestedAttribute
+)
+
+// attributeSpec describes a configurable attribute independently of the schema package it is used in. The data
+// source, the ephemeral resource and the request resource each convert the specs into their own schema attributes,
+// which are optional unless required is set.
+type attributeSpec struct {
+	description string
+	kind        attributeKind
+	required    bool
+	sensitive   bool
+	attributes  map[string]attributeSpec
+}
+
+// requestAttributeSpecs describes the request attributes shared by the curl2 data source and ephemeral resource.
+// `uri`, `http_method` and `response` differ between the two and are declared by each schema.
+func requestAttributeSpecs() map[string]attributeSpec {
+	return map[string]attributeSpec{
+		"json": {
+			description: "JSON object in string format if using POST, PUT or PATCH method. Sent with `Content-Type: application/json`.",
+			kind:        stringAttribute,
+		},
+		"form": {
+			description: "Form fields sent as an `application/x-www-form-urlencoded` body.",
+			kind:        stringMapAttribute,
+		},
+		"multipart": {
+			description: "Fields and files sent as a `multipart/form-data` body.",
+			kind:        nestedAttribute,
+			attributes: map[string]attributeSpec{
+				"fields": {
+					description: "Plain form fields.",
+					kind:        stringMapAttribute,
+				},
+				"files": {
+					description: "File parts as a map of field name to local file path.",
+					kind:        stringMapAttribute,
+				},
+			},
+		},
+		"raw_body": {
+			description: "Body sent as is. Sent with `Content-Type: text/plain` unless `content_type` is set.",
+			kind:        stringAttribute,
+		},
+		"body_base64": {
+			description: "Base64 encoded binary body. Sent with `Content-Type: application/octet-stream` unless `content_type` is set.",
+			kind:        stringAttribute,
+		},
+		"content_type": {
+			description: "Content type of the body, overriding the default of the body mode. Ignored for `multipart`. Only one of `json`, `form`, `multipart`, `raw_body` or `body_base64` can be set.",
+			kind:        stringAttribute,
+		},
+		"auth_type": {
+			description: "Authentication Type, Bearer, Basic, AzureAD, Auth0, OAuth2, AWSv4 or HMAC. AzureAD, Auth0 and OAuth2 fetch a token with the matching provider block and send it as a Bearer token. AWSv4 signs the request with `aws_sigv4` and HMAC with `hmac`.",
+			kind:        stringAttribute,
+		},
+		"bearer_token": {
+			description: "Bearer Token to be used for Authentication.",
+			kind:        stringAttribute,
+			sensitive:   true,
+		},
+		"basic_auth_username": {
+			description: "Username to be used for Basic Authentication.",
+			kind:        stringAttribute,
+		},
+		"basic_auth_password": {
+			description: "Password to be used for Authentication.",
+			kind:        stringAttribute,
+			sensitive:   true,
+		},
+		"token_scopes": {
+			description: "Scopes of the token fetched for the AzureAD and OAuth2 auth types. Example: [\"https://graph.microsoft.com/.default\"]",
+			kind:        stringListAttribute,
+		},
+		"token_audience": {
+			description: "Audience of the token fetched for the Auth0 and OAuth2 auth types.",
+			kind:        stringAttribute,
+		},
+		"aws_sigv4": {
+			description: "AWS Signature Version 4 settings for the AWSv4 auth type.",
+			kind:        nestedAttribute,
+			attributes:  awsSigV4AttributeSpecs(),
+		},
+		"hmac": {
+			description: "HMAC signature settings for the HMAC auth type.",
+			kind:        nestedAttribute,
+			attributes:  hmacAttributeSpecs(),
+		},
+		"headers": {
+			description: "Headers to be added.",
+			kind:        stringMapAttribute,
+		},
+		"expected_status_codes": {
+			description: "Status codes the response must match, given as exact codes like `201`, classes like `2xx` or ranges like `200-299`. By default any status is accepted.",
+			kind:        stringListAttribute,
+		},
+		"extract": {
+			description: "Values to extract from a JSON response body into `response.extracted`, as a map of name to path. Paths use JSONPath (`$.items[0].id`) or gjson (`items.0.id`) syntax. Strings are extracted as is, other values as JSON.",
+			kind:        stringMapAttribute,
+		},
+		"extract_optional": {
+			description: "Names from `extract` whose path may be missing from the response. Missing optional values are left out of `response.extracted`.",
+			kind:        stringListAttribute,
+		},
+		"tls": {
+			description: "TLS settings for this request. Each attribute set here replaces the matching setting of the provider `tls` block.",
+			kind:        nestedAttribute,
+			attributes:  tlsAttributeSpecs(),
+		},
+		"timeouts": {
+			description: "Timeouts for this request, overriding the provider `timeout_ms`.",
+			kind:        nestedAttribute,
+			attributes: map[string]attributeSpec{
+				"connect_ms": {
+					description: "Time allowed to establish the TCP connection in milliseconds. Defaults to 30000.",
+					kind:        int64Attribute,
+				},
+				"tls_handshake_ms": {
+					description: "Time allowed for the TLS handshake in milliseconds. Defaults to 10000.",
+					kind:        int64Attribute,
+				},
+				"response_header_ms": {
+					description: "Time allowed to wait for the response headers after the request is sent in milliseconds. Defaults to 0, no timeout.",
+					kind:        int64Attribute,
+				},
+				"total_ms": {
+					description: "Overall time allowed for the request in milliseconds, including retries and reading the body. Replaces the provider `timeout_ms` for this request. Defaults to 0, no timeout.",
+					kind:        int64Attribute,
+				},
+			},
+		},
+	}
+}
+
+// tlsAttributeSpecs describes the attributes of the curl2 `tls` attribute.
+func tlsAttributeSpecs() map[string]attributeSpec {
+	specs := map[string]attributeSpec{}
+	for name, description := range tlsAttributeDescriptions {
+		kind := stringAttribute
+		if name == "cipher_suites" {
+			kind = stringListAttribute
+		}
+		specs[name] = attributeSpec{
+			description: description,
+			kind:        kind,
+			sensitive:   tlsSensitiveAttributes[name],
+		}
+	}
+	return specs
+}
+
+// awsSigV4AttributeSpecs describes the attributes of `aws_sigv4`.
+func awsSigV4AttributeSpecs() map[string]attributeSpec {
+	specs := map[string]attributeSpec{}
+	for name, description := range awsSigV4AttributeDescriptions {
+		specs[name] = attributeSpec{
+			description: description,
+			kind:        stringAttribute,
+			sensitive:   awsSigV4SensitiveAttributes[name],
+		}
+	}
+	return specs
+}
+
+// hmacAttributeSpecs describes the attributes of `hmac`.
+func hmacAttributeSpecs() map[string]attributeSpec {
+	specs := map[string]attributeSpec{
+		"signed_components": {
+			description: hmacSignedComponentsDescription,
+			kind:        stringListAttribute,
+		},
+	}
+	for name, description := range hmacAttributeDescriptions {
+		specs[name] = attributeSpec{
+			description: description,
+			kind:        stringAttribute,
+			required:    name == "key",
+			sensitive:   name == "key",
+		}
+	}
+	return specs
+}
@@ -0,0 +1,199 @@
+package curl2
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type multipartModel struct {
+	Fields types.Map `tfsdk:"fields"`
+	Files  types.Map `tfsdk:"files"`
+}
+
+// requestBodyConfig holds the mutually exclusive body attributes of a curl2 request.
+type requestBodyConfig struct {
+	JSON        types.String
+	Form        types.Map
+	Multipart   types.Object
+	RawBody     types.String
+	BodyBase64  types.String
+	ContentType types.String
+}
+
+// requestBody is an encoded request body along with the content type describing it.
+type requestBody struct {
+	data        []byte
+	contentType string
+}
+
+// buildRequestBody encodes whichever body mode is configured. It returns a nil body when none is set.
+func buildRequestBody(ctx context.Context, config requestBodyConfig) (*requestBody, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var modes []string
+	if config.JSON.ValueString() != "" {
+		modes = append(modes, "json")
+	}
+	if !config.Form.IsNull() && !config.Form.IsUnknown() {
+		modes = append(modes, "form")
+	}
+	if !config.Multipart.IsNull() && !config.Multipart.IsUnknown() {
+		modes = append(modes, "multipart")
+	}
+	if !config.RawBody.IsNull() && !config.RawBody.IsUnknown() {
+		modes = append(modes, "raw_body")
+	}
+	if !config.BodyBase64.IsNull() && !config.BodyBase64.IsUnknown() {
+		modes = append(modes, "body_base64")
+	}
+
+	if len(modes) > 1 {
+		diags.AddAttributeError(
+			path.Root(modes[1]),
+			"Conflicting Request Body",
+			fmt.Sprintf("Only one of json, form, multipart, raw_body or body_base64 can be set, got: %s", strings.Join(modes, ", ")),
+		)
+		return nil, diags
+	}
+	if len(modes) == 0 {
+		return nil, diags
+	}
+
+	var body *requestBody
+	switch modes[0] {
+	case "json":
+		var jsonData interface{}
+		if err := json.Unmarshal([]byte(config.JSON.ValueString()), &jsonData); err != nil {
+			diags.AddError(
+				"Failed to parse JSON parameter",
+				err.Error(),
+			)
+			return nil, diags
+		}
+
+		data, err := json.Marshal(jsonData)
+		if err != nil {
+			diags.AddError(
+				"Failed to marshal JSON data",
+				err.Error(),
+			)
+			return nil, diags
+		}
+		body = &requestBody{data: data, contentType: "application/json"}
+	case "form":
+		var fields map[string]string
+		diags.Append(config.Form.ElementsAs(ctx, &fields, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		values := url.Values{}
+		for key, value := range fields {
+			values.Set(key, value)
+		}
+		body = &requestBody{data: []byte(values.Encode()), contentType: "application/x-www-form-urlencoded"}
+	case "multipart":
+		var parts multipartModel
+		diags.Append(config.Multipart.As(ctx, &parts, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		var fields, files map[string]string
+		if !parts.Fields.IsNull() && !parts.Fields.IsUnknown() {
+			diags.Append(parts.Fields.ElementsAs(ctx, &fields, false)...)
+		}
+		if !parts.Files.IsNull() && !parts.Files.IsUnknown() {
+			diags.Append(parts.Files.ElementsAs(ctx, &files, false)...)
+		}
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		data, contentType, err := encodeMultipart(fields, files)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("multipart"),
+				"Failed to build multipart body",
+				err.Error(),
+			)
+			return nil, diags
+		}
+		body = &requestBody{data: data, contentType: contentType}
+	case "raw_body":
+		body = &requestBody{data: []byte(config.RawBody.ValueString()), contentType: "text/plain"}
+	case "body_base64":
+		data, err := base64.StdEncoding.DecodeString(config.BodyBase64.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("body_base64"),
+				"Failed to decode base64 body",
+				err.Error(),
+			)
+			return nil, diags
+		}
+		body = &requestBody{data: data, contentType: "application/octet-stream"}
+	}
+
+	// The multipart boundary is part of its content type, so it cannot be replaced.
+	if config.ContentType.ValueString() != "" && modes[0] != "multipart" {
+		body.contentType = config.ContentType.ValueString()
+	}
+
+	return body, diags
+}
+
+// encodeMultipart writes the fields followed by the files, read from their local paths, as multipart/form-data.
+func encodeMultipart(fields map[string]string, files map[string]string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	for _, name := range sortedKeys(fields) {
+		if err := writer.WriteField(name, fields[name]); err != nil {
+			return nil, "", err
+		}
+	}
+
+	for _, name := range sortedKeys(files) {
+		content, err := os.ReadFile(files[name])
+		if err != nil {
+			return nil, "", err
+		}
+
+		part, err := writer.CreateFormFile(name, filepath.Base(files[name]))
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err = part.Write(content); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,531 @@
+package curl2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	_ resource.Resource                   = &curl2RequestResource{}
+	_ resource.ResourceWithConfigure      = &curl2RequestResource{}
+	_ resource.ResourceWithModifyPlan     = &curl2RequestResource{}
+	_ resource.ResourceWithValidateConfig = &curl2RequestResource{}
+)
+
+func NewCurl2RequestResource() resource.Resource {
+	return &curl2RequestResource{}
+}
+
+type curl2RequestResourceModel struct {
+	ID                types.String `tfsdk:"id"`
+	IDJSONPath        types.String `tfsdk:"id_json_path"`
+	AuthType          types.String `tfsdk:"auth_type"`
+	BearerToken       types.String `tfsdk:"bearer_token"`
+	BasicAuthUsername types.String `tfsdk:"basic_auth_username"`
+	BasicAuthPassword types.String `tfsdk:"basic_auth_password"`
+	TokenScopes       types.List   `tfsdk:"token_scopes"`
+	TokenAudience     types.String `tfsdk:"token_audience"`
+	AWSSigV4          types.Object `tfsdk:"aws_sigv4"`
+	HMAC              types.Object `tfsdk:"hmac"`
+	Create            types.Object `tfsdk:"create"`
+	Read              types.Object `tfsdk:"read"`
+	Update            types.Object `tfsdk:"update"`
+	Destroy           types.Object `tfsdk:"destroy"`
+	Response          types.Object `tfsdk:"response"`
+	ReadResponse      types.Object `tfsdk:"read_response"`
+}
+
+type requestBlockModel struct {
+	HTTPMethod types.String `tfsdk:"http_method"`
+	URI        types.String `tfsdk:"uri"`
+	Body       types.String `tfsdk:"body"`
+	Headers    types.Map    `tfsdk:"headers"`
+}
+
+type curl2RequestResource struct {
+	client       *HttpClient
+	providerData *curl2ProviderData
+}
+
+func (c *curl2RequestResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_request"
+}
+
+func requestBlock(description string) schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: description,
+		Attributes: map[string]schema.Attribute{
+			"http_method": schema.StringAttribute{
+				Description: "HTTP method like GET, POST, PUT, DELETE, PATCH.",
+				Required:    true,
+			},
+			"uri": schema.StringAttribute{
+				Description: "URI of the request. Outside of the create block, `{id}` is replaced with the resource id and `{response.<path>}` with the value found at the JSONPath or gjson path in the body of `response`, each escaped as a path segment.",
+				Required:    true,
+			},
+			"body": schema.StringAttribute{
+				Description: "Request body sent as is.",
+				Optional:    true,
+			},
+			"headers": schema.MapAttribute{
+				Description: "Headers to be added.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (c *curl2RequestResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a remote object through separate create, read, update and destroy HTTP requests.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the resource, read from the create response with `id_json_path` or generated on create.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id_json_path": schema.StringAttribute{
+				Description: "Path of the resource id in the JSON body of the create response, in JSONPath (`$.data.id`) or gjson (`data.id`) syntax. Defaults to a generated UUID. Changing it replaces the resource.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"auth_type": schema.StringAttribute{
+				Description: "Authentication Type, Bearer, Basic, AzureAD, Auth0, OAuth2, AWSv4 or HMAC. AzureAD, Auth0 and OAuth2 fetch a token with the matching provider block and send it as a Bearer token. AWSv4 signs the request with `aws_sigv4` and HMAC with `hmac`. Applies to every request.",
+				Optional:    true,
+			},
+			"bearer_token": schema.StringAttribute{
+				Description: "Bearer Token to be used for Authentication.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"basic_auth_username": schema.StringAttribute{
+				Description: "Username to be used for Basic Authentication.",
+				Optional:    true,
+			},
+			"basic_auth_password": schema.StringAttribute{
+				Description: "Password to be used for Authentication.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"token_scopes": schema.ListAttribute{
+				Description: "Scopes of the token fetched for the AzureAD and OAuth2 auth types. Example: [\"https://graph.microsoft.com/.default\"]",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"token_audience": schema.StringAttribute{
+				Description: "Audience of the token fetched for the Auth0 and OAuth2 auth types.",
+				Optional:    true,
+			},
+			"aws_sigv4": schema.SingleNestedAttribute{
+				Description: "AWS Signature Version 4 settings for the AWSv4 auth type.",
+				Optional:    true,
+				Attributes:  resourceAttributes(awsSigV4AttributeSpecs()),
+			},
+			"hmac": schema.SingleNestedAttribute{
+				Description: "HMAC signature settings for the HMAC auth type.",
+				Optional:    true,
+				Attributes:  resourceAttributes(hmacAttributeSpecs()),
+			},
+			"response": schema.ObjectAttribute{
+				AttributeTypes: responseAttrTypes(),
+				Description:    "Value returned by the most recent create or update request.",
+				Computed:       true,
+			},
+			"read_response": schema.ObjectAttribute{
+				AttributeTypes: responseAttrTypes(),
+				Description:    "Value returned by the read request on the last refresh. Empty if no read block is configured.",
+				Computed:       true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"create":  requestBlock("Request sent when the resource is created. Required."),
+			"read":    requestBlock("Request sent when the resource is refreshed. A 404 or 410 response removes the resource from state."),
+			"update":  requestBlock("Request sent when the resource changes. Without it, any change to the create block replaces the resource."),
+			"destroy": requestBlock("Request sent when the resource is destroyed. A 404 or 410 response is treated as already deleted."),
+		},
+	}
+}
+
+func (c *curl2RequestResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config curl2RequestResourceModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Create.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("create"),
+			"Missing create block",
+			"The curl2_request resource requires a create block",
+		)
+	}
+}
+
+func (c *curl2RequestResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state curl2RequestResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Update.IsNull() && !plan.Create.Equal(state.Create) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("create"))
+	}
+}
+
+func (c *curl2RequestResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan curl2RequestResourceModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	idPath := plan.IDJSONPath.ValueString()
+	if idPath == "" {
+		id, err := uuid.GenerateUUID()
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to generate resource id",
+				err.Error(),
+			)
+			return
+		}
+		plan.ID = types.StringValue(id)
+	}
+
+	r, body, diags := c.send(ctx, plan, plan.Create)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if idPath != "" {
+		result, found, err := lookupJSON(body, idPath)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("id_json_path"),
+				"Invalid Id Path",
+				err.Error(),
+			)
+			return
+		}
+		if !found || jsonResultString(result) == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("id_json_path"),
+				"Resource Id Not Found",
+				fmt.Sprintf("Path %q does not exist in the create response body:\n%s", idPath, redactBody(body, defaultSnippetBytes)),
+			)
+			return
+		}
+		plan.ID = types.StringValue(jsonResultString(result))
+	}
+
+	plan.Response, diags = responseValue(r.Request.URL.String(), r, body, nil)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.ReadResponse = types.ObjectNull(responseAttrTypes())
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (c *curl2RequestResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state curl2RequestResourceModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.Read.IsNull() {
+		return
+	}
+
+	r, body, diags := c.send(ctx, state, state.Read)
+	if r != nil && isGone(r.StatusCode) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.ReadResponse, diags = responseValue(r.Request.URL.String(), r, body, nil)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (c *curl2RequestResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state curl2RequestResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Response = state.Response
+	plan.ReadResponse = state.ReadResponse
+
+	if !plan.Update.IsNull() {
+		r, body, diags := c.send(ctx, plan, plan.Update)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		plan.Response, diags = responseValue(r.Request.URL.String(), r, body, nil)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	diags := resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (c *curl2RequestResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state curl2RequestResourceModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.Destroy.IsNull() {
+		return
+	}
+
+	r, _, diags := c.send(ctx, state, state.Destroy)
+	if r != nil && isGone(r.StatusCode) {
+		return
+	}
+	resp.Diagnostics.Append(diags...)
+}
+
+func (c *curl2RequestResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c.providerData = req.ProviderData.(*curl2ProviderData)
+	c.client = c.providerData.client
+}
+
+// send issues the request described by block using the resource level authentication.
+// Responses with a status code of 400 or above are reported as errors.
+func (c *curl2RequestResource) send(ctx context.Context, model curl2RequestResourceModel, block types.Object) (*http.Response, []byte, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var request requestBlockModel
+	diags.Append(block.As(ctx, &request, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+
+	var body interface{}
+	if request.Body.ValueString() != "" {
+		body = []byte(request.Body.ValueString())
+	}
+
+	uri, uriDiags := expandURI(model, request.URI.ValueString())
+	diags.Append(uriDiags...)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+
+	newReq, err := retryablehttp.NewRequestWithContext(ctx, request.HTTPMethod.ValueString(), uri, body)
+	if err != nil {
+		diags.AddError(
+			"Unable to create new http request",
+			err.Error(),
+		)
+		return nil, nil, diags
+	}
+
+	headers, headerDiags := headersFromMap(ctx, request.Headers)
+	diags.Append(headerDiags...)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+	setHeaders(newReq, headers)
+
+	var tokenScopes []string
+	if !model.TokenScopes.IsNull() && !model.TokenScopes.IsUnknown() {
+		diags.Append(model.TokenScopes.ElementsAs(ctx, &tokenScopes, false)...)
+		if diags.HasError() {
+			return nil, nil, diags
+		}
+	}
+
+	diags.Append(applyAuth(ctx, newReq, authConfig{
+		AuthType:          model.AuthType.ValueString(),
+		BearerToken:       model.BearerToken.ValueString(),
+		BasicAuthUsername: model.BasicAuthUsername.ValueString(),
+		BasicAuthPassword: model.BasicAuthPassword.ValueString(),
+		TokenScopes:       tokenScopes,
+		TokenAudience:     model.TokenAudience.ValueString(),
+		AWSSigV4:          model.AWSSigV4,
+		HMAC:              model.HMAC,
+	}, c.providerData)...)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+
+	r, responseData, err := c.client.Do(newReq)
+	if err != nil {
+		diags.AddError(
+			"Error calling api",
+			err.Error(),
+		)
+		return r, nil, diags
+	}
+
+	if r.StatusCode >= 400 {
+		diags.AddError(
+			"Unexpected response status",
+			unexpectedStatusDetail(r, responseData, nil),
+		)
+	}
+
+	return r, responseData, diags
+}
+
+// isGone reports whether the status code means the remote object no longer exists.
+func isGone(statusCode int) bool {
+	return statusCode == http.StatusNotFound || statusCode == http.StatusGone
+}
+
+// uriPlaceholder matches the `{id}` and `{response.<path>}` placeholders of request block URIs.
+var uriPlaceholder = regexp.MustCompile(`\{(id|response\.[^{}]+)\}`)
+
+// expandURI replaces the placeholders of uri with the resource id and values of the JSON body in `response`, the
+// body of the most recent create or update request.
+func expandURI(model curl2RequestResourceModel, uri string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	expanded := uriPlaceholder.ReplaceAllStringFunc(uri, func(placeholder string) string {
+		value, err := model.placeholderValue(placeholder[1 : len(placeholder)-1])
+		if err != nil {
+			diags.AddError(
+				"Unable to expand URI placeholder",
+				fmt.Sprintf("%s in %q: %s", placeholder, uri, err),
+			)
+			return placeholder
+		}
+		return url.PathEscape(value)
+	})
+	return expanded, diags
+}
+
+func (m curl2RequestResourceModel) placeholderValue(name string) (string, error) {
+	if name == "id" {
+		if m.ID.IsNull() || m.ID.IsUnknown() {
+			return "", errors.New("the id is only known after the create request")
+		}
+		return m.ID.ValueString(), nil
+	}
+
+	body, ok := m.Response.Attributes()["body"].(types.String)
+	if m.Response.IsNull() || m.Response.IsUnknown() || !ok {
+		return "", errors.New("the response is only known after the create request")
+	}
+
+	expr := strings.TrimPrefix(name, "response.")
+	result, found, err := lookupJSON([]byte(body.ValueString()), expr)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("path %q does not exist in the response body", expr)
+	}
+	return jsonResultString(result), nil
+}
+
+// resourceAttributes converts attribute specs into resource schema attributes.
+func resourceAttributes(specs map[string]attributeSpec) map[string]schema.Attribute {
+	attributes := map[string]schema.Attribute{}
+	for name, spec := range specs {
+		switch spec.kind {
+		case stringAttribute:
+			attributes[name] = schema.StringAttribute{
+				Description: spec.description,
+				Required:    spec.required,
+				Optional:    !spec.required,
+				Sensitive:   spec.sensitive,
+			}
+		case int64Attribute:
+			attributes[name] = schema.Int64Attribute{
+				Description: spec.description,
+				Required:    spec.required,
+				Optional:    !spec.required,
+				Sensitive:   spec.sensitive,
+			}
+		case stringListAttribute:
+			attributes[name] = schema.ListAttribute{
+				Description: spec.description,
+				ElementType: types.StringType,
+				Required:    spec.required,
+				Optional:    !spec.required,
+				Sensitive:   spec.sensitive,
+			}
+		case stringMapAttribute:
+			attributes[name] = schema.MapAttribute{
+				Description: spec.description,
+				ElementType: types.StringType,
+				Required:    spec.required,
+				Optional:    !spec.required,
+				Sensitive:   spec.sensitive,
+			}
+		case nestedAttribute:
+			attributes[name] = schema.SingleNestedAttribute{
+				Description: spec.description,
+				Required:    spec.required,
+				Optional:    !spec.required,
+				Sensitive:   spec.sensitive,
+				Attributes:  resourceAttributes(spec.attributes),
+			}
+		}
+	}
+	return attributes
+}
@@ -0,0 +1,133 @@
+package curl2
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestResourceURIPlaceholders(t *testing.T) {
+	createBody := `{"data":{"id":"item 1","owner":{"name":"team/a"}},"version":3}`
+
+	cases := []struct {
+		name     string
+		id       types.String
+		response bool
+		uri      string
+
+		wantPath string
+		wantErr  string
+	}{
+		{
+			name:     "static uri",
+			id:       types.StringValue("4f0e"),
+			response: true,
+			uri:      "/items/static",
+			wantPath: "/items/static",
+		},
+		{
+			name:     "resource id",
+			id:       types.StringValue("item 1"),
+			response: true,
+			uri:      "/items/{id}",
+			wantPath: "/items/item%201",
+		},
+		{
+			name:     "response values",
+			id:       types.StringValue("item 1"),
+			response: true,
+			uri:      "/owners/{response.$.data.owner.name}/items/{response.data.id}?version={response.version}",
+			wantPath: "/owners/team%2Fa/items/item%201?version=3",
+		},
+		{
+			name:     "missing response path",
+			id:       types.StringValue("item 1"),
+			response: true,
+			uri:      "/items/{response.data.missing}",
+			wantErr:  `path "data.missing" does not exist in the response body`,
+		},
+		{
+			name:    "id before create",
+			id:      types.StringUnknown(),
+			uri:     "/items/{id}",
+			wantErr: "the id is only known after the create request",
+		},
+		{
+			name:    "response before create",
+			id:      types.StringValue("4f0e"),
+			uri:     "/items/{response.data.id}",
+			wantErr: "the response is only known after the create request",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.URL.RequestURI())
+				w.Write([]byte(createBody))
+			}))
+			defer server.Close()
+
+			ctx := context.Background()
+			model := curl2RequestResourceModel{
+				ID:       tc.id,
+				Response: types.ObjectUnknown(responseAttrTypes()),
+			}
+			if tc.response {
+				created := &http.Response{
+					StatusCode: http.StatusCreated,
+					Header:     http.Header{},
+					Request:    httptest.NewRequest(http.MethodPost, server.URL+"/items", nil),
+				}
+				response, diags := responseValue(server.URL+"/items", created, []byte(createBody), nil)
+				if diags.HasError() {
+					t.Fatal(diags)
+				}
+				model.Response = response
+			}
+			block, diags := types.ObjectValueFrom(ctx, requestBlockAttrTypes(), requestBlockModel{
+				HTTPMethod: types.StringValue(http.MethodGet),
+				URI:        types.StringValue(server.URL + tc.uri),
+				Body:       types.StringNull(),
+				Headers:    types.MapNull(types.StringType),
+			})
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+
+			pd := &curl2ProviderData{client: NewClient(ApiClientOpts{}), tokens: newTokenCache(false, 0)}
+			c := &curl2RequestResource{client: pd.client, providerData: pd}
+			_, _, diags = c.send(ctx, model, block)
+
+			if tc.wantErr != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Detail(), tc.wantErr) {
+					t.Fatalf("diagnostics = %v, want %q", diags, tc.wantErr)
+				}
+				if len(requests) != 0 {
+					t.Errorf("%d requests sent, want none", len(requests))
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			if len(requests) != 1 || requests[0] != tc.wantPath {
+				t.Errorf("requests = %v, want %s", requests, tc.wantPath)
+			}
+		})
+	}
+}
+
+func requestBlockAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"http_method": types.StringType,
+		"uri":         types.StringType,
+		"body":        types.StringType,
+		"headers":     types.MapType{ElemType: types.StringType},
+	}
+}
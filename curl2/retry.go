@@ -0,0 +1,114 @@
+package curl2
+
+import (
+	"context"
+	"github.com/hashicorp/go-retryablehttp"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	backoffExponential = "exponential"
+	backoffLinear      = "linear"
+)
+
+type requestMethodKey struct{}
+
+// retryPolicy decides whether a request is retried based on the provider retry configuration.
+func retryPolicy(opts ApiClientOpts) retryablehttp.CheckRetry {
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+
+		if opts.idempotentOnly {
+			if method, ok := ctx.Value(requestMethodKey{}).(string); ok && !isIdempotent(method) {
+				return false, nil
+			}
+		}
+
+		if err != nil || resp == nil || len(opts.retryableStatusCodes) == 0 {
+			return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+		}
+
+		for _, code := range opts.retryableStatusCodes {
+			if resp.StatusCode == code {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// backoffPolicy computes the wait before the next attempt. Retry-After on a 429 or 503
+// response takes precedence when enabled, capped at max so a server cannot stall the
+// provider, otherwise a linear or exponential delay bounded by min and max is used,
+// optionally randomised between min and the computed delay.
+func backoffPolicy(opts ApiClientOpts) retryablehttp.Backoff {
+	return func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+		if opts.respectRetryAfter && resp != nil &&
+			(resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+			if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				if wait > max {
+					wait = max
+				}
+				return wait
+			}
+		}
+
+		var mult float64
+		if opts.backoff == backoffLinear {
+			mult = float64(attemptNum+1) * float64(min)
+		} else {
+			mult = math.Pow(2, float64(attemptNum)) * float64(min)
+		}
+
+		wait := time.Duration(mult)
+		if float64(wait) != mult || wait > max {
+			wait = max
+		}
+
+		if opts.jitter && wait > min {
+			wait = min + time.Duration(rand.Int63n(int64(wait-min)+1))
+		}
+		return wait
+	}
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		wait := time.Until(at)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+// isIdempotent reports whether requests with the given method may safely be sent more than once.
+func isIdempotent(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodPost, http.MethodPatch:
+		return false
+	default:
+		return true
+	}
+}
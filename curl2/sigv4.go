@@ -0,0 +1,255 @@
+package curl2
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"net/http"
+	"net/url"
+	"os"
+	pathpkg "path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+	sigV4DateFormat = "20060102"
+)
+
+// awsSigV4Model maps the `aws_sigv4` attribute of the curl2 data source and request resource.
+type awsSigV4Model struct {
+	Region          types.String `tfsdk:"region"`
+	Service         types.String `tfsdk:"service"`
+	AccessKeyID     types.String `tfsdk:"access_key_id"`
+	SecretAccessKey types.String `tfsdk:"secret_access_key"`
+	SessionToken    types.String `tfsdk:"session_token"`
+}
+
+// sigV4Config holds the credentials and scope used to sign requests with AWS Signature Version 4.
+type sigV4Config struct {
+	Region          string
+	Service         string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// awsSigV4AttributeDescriptions documents the attributes of `aws_sigv4`.
+var awsSigV4AttributeDescriptions = map[string]string{
+	"region":            "AWS region of the endpoint, for example `eu-west-1`. Defaults to the ENV variable `AWS_REGION` or `AWS_DEFAULT_REGION`.",
+	"service":           "Signing name of the AWS service, for example `execute-api` for API Gateway or `s3`.",
+	"access_key_id":     "AWS access key ID. Defaults to the ENV variable `AWS_ACCESS_KEY_ID`.",
+	"secret_access_key": "AWS secret access key. Defaults to the ENV variable `AWS_SECRET_ACCESS_KEY`.",
+	"session_token":     "Session token of temporary credentials. Defaults to the ENV variable `AWS_SESSION_TOKEN`.",
+}
+
+// awsSigV4SensitiveAttributes lists the aws_sigv4 attributes holding secrets.
+var awsSigV4SensitiveAttributes = map[string]bool{
+	"secret_access_key": true,
+	"session_token":     true,
+}
+
+// sigV4ConfigFromObject decodes an `aws_sigv4` attribute, falling back to the standard AWS environment variables
+// for every setting that is not configured.
+func sigV4ConfigFromObject(ctx context.Context, object types.Object) (sigV4Config, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var m awsSigV4Model
+	if !object.IsNull() && !object.IsUnknown() {
+		diags.Append(object.As(ctx, &m, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return sigV4Config{}, diags
+		}
+	}
+
+	config := sigV4Config{
+		Region:          valueOrEnv(m.Region, "AWS_REGION"),
+		Service:         m.Service.ValueString(),
+		AccessKeyID:     valueOrEnv(m.AccessKeyID, "AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: valueOrEnv(m.SecretAccessKey, "AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    valueOrEnv(m.SessionToken, "AWS_SESSION_TOKEN"),
+	}
+	if config.Region == "" {
+		config.Region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+
+	if err := config.validate(); err != nil {
+		diags.AddAttributeError(
+			path.Root("aws_sigv4"),
+			"Invalid AWS SigV4 Configuration",
+			err.Error(),
+		)
+	}
+
+	return config, diags
+}
+
+func (c sigV4Config) validate() error {
+	switch {
+	case c.Region == "":
+		return fmt.Errorf("region must be provided, either in aws_sigv4 or as ENV variable AWS_REGION")
+	case c.Service == "":
+		return fmt.Errorf("service must be provided in aws_sigv4")
+	case c.AccessKeyID == "" || c.SecretAccessKey == "":
+		return fmt.Errorf("access_key_id and secret_access_key must be provided, either in aws_sigv4 or as ENV variables AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	return nil
+}
+
+// signSigV4 adds the X-Amz-Date, X-Amz-Security-Token and Authorization headers of AWS Signature Version 4 to req.
+// Every header already set on the request is signed, so it must be called once all headers are in place.
+func signSigV4(req *http.Request, body []byte, config sigV4Config, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format(sigV4TimeFormat)
+	payloadHash := sha256Hex(body)
+
+	req.Header.Del("Authorization")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", config.SessionToken)
+	}
+	if config.Service == "s3" {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	canonicalRequest, signedHeaders := sigV4CanonicalRequest(req, payloadHash, config.Service)
+
+	scope := strings.Join([]string{now.Format(sigV4DateFormat), config.Region, config.Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{sigV4Algorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+config.SecretAccessKey), now.Format(sigV4DateFormat))
+	key = hmacSHA256(key, config.Region)
+	key = hmacSHA256(key, config.Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// sigV4CanonicalRequest builds the canonical request and the list of signed headers.
+func sigV4CanonicalRequest(req *http.Request, payloadHash string, service string) (string, string) {
+	headers := map[string][]string{}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		// These headers are commonly rewritten by proxies and are not signed.
+		if name == "authorization" || name == "user-agent" || name == "expect" || name == "x-amzn-trace-id" {
+			continue
+		}
+		for _, value := range values {
+			headers[name] = append(headers[name], strings.Join(strings.Fields(value), " "))
+		}
+	}
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers["host"] = []string{host}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.Join(headers[name], ",") + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		sigV4CanonicalURI(req.URL, service),
+		sigV4CanonicalQuery(req.URL),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	return canonicalRequest, signedHeaders
+}
+
+// sigV4CanonicalURI encodes the path. S3 object keys are signed as is and encoded once, every other service signs
+// the normalized path as sent on the wire encoded again, so each segment ends up encoded twice.
+func sigV4CanonicalURI(u *url.URL, service string) string {
+	if service == "s3" {
+		if u.Path == "" {
+			return "/"
+		}
+		return uriEncode(u.Path, false)
+	}
+
+	return sigV4CanonicalPath(u.EscapedPath())
+}
+
+// sigV4CanonicalPath normalizes an escaped path, removing `.` and `..` segments and duplicate slashes, and encodes
+// every segment.
+func sigV4CanonicalPath(escapedPath string) string {
+	if escapedPath == "" {
+		return "/"
+	}
+
+	trailingSlash := strings.HasSuffix(escapedPath, "/")
+	p := pathpkg.Clean("/" + escapedPath)
+	if trailingSlash && p != "/" {
+		p += "/"
+	}
+
+	return uriEncode(p, false)
+}
+
+// sigV4CanonicalQuery sorts the query parameters by name and value, encoding both.
+func sigV4CanonicalQuery(u *url.URL) string {
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		query = u.Query()
+	}
+
+	var params []string
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(params)
+
+	return strings.Join(params, "&")
+}
+
+// uriEncode percent-encodes every byte except the unreserved characters of RFC 3986, and `/` unless encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,181 @@
+package curl2
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Credentials, scope and time shared by every case of the AWS SigV4 test suite.
+var sigV4TestSuiteConfig = sigV4Config{
+	Region:          "us-east-1",
+	Service:         "service",
+	AccessKeyID:     "AKIDEXAMPLE",
+	SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+}
+
+var sigV4TestSuiteTime = time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+func TestSignSigV4TestSuite(t *testing.T) {
+	cases := []struct {
+		name             string
+		method           string
+		url              string
+		contentType      string
+		body             string
+		canonicalRequest string
+		authorization    string
+	}{
+		{
+			name:   "get-vanilla",
+			method: "GET",
+			url:    "https://example.amazonaws.com/",
+			canonicalRequest: "GET\n/\n\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\nhost;x-amz-date\n" +
+				emptyPayloadHash,
+			authorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name:   "get-vanilla-query-order-key-case",
+			method: "GET",
+			url:    "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			canonicalRequest: "GET\n/\nParam1=value1&Param2=value2\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\nhost;x-amz-date\n" +
+				emptyPayloadHash,
+			authorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+		{
+			name:   "post-vanilla",
+			method: "POST",
+			url:    "https://example.amazonaws.com/",
+			canonicalRequest: "POST\n/\n\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\nhost;x-amz-date\n" +
+				emptyPayloadHash,
+			authorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+		{
+			name:        "post-x-www-form-urlencoded",
+			method:      "POST",
+			url:         "https://example.amazonaws.com/",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "Param1=value1",
+			canonicalRequest: "POST\n/\n\ncontent-type:application/x-www-form-urlencoded\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\ncontent-type;host;x-amz-date\n" +
+				"9095672bbd1f56dfc5b65f3e153adc8731a4a654192329106275f4c7b24d0b6e",
+			authorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=content-type;host;x-amz-date, Signature=ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a",
+		},
+		// The normalize-path cases below all sign the root path, so they share the signature of get-vanilla.
+		{
+			name:   "normalize-path/get-relative",
+			method: "GET",
+			url:    "https://example.amazonaws.com/example/..",
+			authorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name:   "normalize-path/get-relative-relative",
+			method: "GET",
+			url:    "https://example.amazonaws.com/example1/example2/../..",
+			authorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name:   "normalize-path/get-slash",
+			method: "GET",
+			url:    "https://example.amazonaws.com//",
+			authorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name:   "normalize-path/get-slash-dot-slash",
+			method: "GET",
+			url:    "https://example.amazonaws.com/./",
+			authorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header = http.Header{}
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+
+			signSigV4(req, []byte(tc.body), sigV4TestSuiteConfig, sigV4TestSuiteTime)
+
+			if tc.canonicalRequest != "" {
+				canonicalRequest, _ := sigV4CanonicalRequest(req, sha256Hex([]byte(tc.body)), sigV4TestSuiteConfig.Service)
+				if canonicalRequest != tc.canonicalRequest {
+					t.Errorf("canonical request:\n%s\nwant:\n%s", canonicalRequest, tc.canonicalRequest)
+				}
+			}
+			if got := req.Header.Get("Authorization"); got != tc.authorization {
+				t.Errorf("Authorization = %s, want %s", got, tc.authorization)
+			}
+			if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+				t.Errorf("X-Amz-Date = %s, want 20150830T123600Z", got)
+			}
+		})
+	}
+}
+
+// The test suite gives the path of the request line, which is the path sent on the wire.
+func TestSigV4CanonicalPathTestSuite(t *testing.T) {
+	cases := map[string]struct {
+		requestPath string
+		want        string
+	}{
+		"normalize-path/get-space":               {"/example space/", "/example%20space/"},
+		"normalize-path/get-utf8":                {"/ሴ", "/%E1%88%B4"},
+		"normalize-path/get-slashes":             {"//example//", "/example/"},
+		"normalize-path/get-slash-pointless-dot": {"/./example", "/example"},
+		"normalize-path/get-unreserved": {
+			"/-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz",
+			"/-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := sigV4CanonicalPath(tc.requestPath); got != tc.want {
+				t.Errorf("sigV4CanonicalPath(%q) = %s, want %s", tc.requestPath, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSigV4CanonicalURI(t *testing.T) {
+	cases := []struct {
+		name    string
+		url     string
+		service string
+		want    string
+	}{
+		{"escaped segments are encoded twice", "https://example.amazonaws.com/example%20space/caf%C3%A9", "execute-api", "/example%2520space/caf%25C3%25A9"},
+		{"escaped slash stays in its segment", "https://example.amazonaws.com/a%2Fb/c", "es", "/a%252Fb/c"},
+		{"s3 keys are encoded once", "https://bucket.s3.amazonaws.com/example%20space/caf%C3%A9", "s3", "/example%20space/caf%C3%A9"},
+		{"s3 keys are not normalized", "https://bucket.s3.amazonaws.com/a//b/../c", "s3", "/a//b/../c"},
+		{"empty path", "https://example.amazonaws.com", "execute-api", "/"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sigV4CanonicalURI(u, tc.service); got != tc.want {
+				t.Errorf("sigV4CanonicalURI(%s, %s) = %s, want %s", tc.url, tc.service, got, tc.want)
+			}
+		})
+	}
+}
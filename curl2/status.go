@@ -0,0 +1,60 @@
+package curl2
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// statusMatches reports whether code matches any of the patterns. A pattern is either an exact code
+// such as "201", a class such as "2xx" or an inclusive range such as "200-299".
+func statusMatches(code int, patterns []string) (bool, error) {
+	for _, pattern := range patterns {
+		low, high, err := parseStatusPattern(pattern)
+		if err != nil {
+			return false, err
+		}
+		if code >= low && code <= high {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func parseStatusPattern(pattern string) (int, int, error) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+
+	if len(pattern) == 3 && strings.HasSuffix(pattern, "xx") {
+		class, err := strconv.Atoi(pattern[:1])
+		if err == nil && class >= 1 && class <= 5 {
+			return class * 100, class*100 + 99, nil
+		}
+	}
+
+	if from, to, found := strings.Cut(pattern, "-"); found {
+		low, lowErr := strconv.Atoi(strings.TrimSpace(from))
+		high, highErr := strconv.Atoi(strings.TrimSpace(to))
+		if lowErr == nil && highErr == nil && low <= high {
+			return low, high, nil
+		}
+	}
+
+	if code, err := strconv.Atoi(pattern); err == nil {
+		return code, code, nil
+	}
+
+	return 0, 0, fmt.Errorf("invalid status code pattern %q, expected a code like 200, a class like 2xx or a range like 200-299", pattern)
+}
+
+// unexpectedStatusDetail describes a response whose status was not expected, with a redacted body snippet.
+func unexpectedStatusDetail(r *http.Response, body []byte, expected []string) string {
+	detail := fmt.Sprintf("%s %s returned status %s", r.Request.Method, redactURL(r.Request.URL), r.Status)
+	if len(expected) > 0 {
+		detail += fmt.Sprintf(", expected one of [%s]", strings.Join(expected, ", "))
+	}
+	if len(body) > 0 {
+		detail += ".\n\nResponse body:\n" + redactBody(body, defaultSnippetBytes)
+	}
+	return detail
+}
@@ -0,0 +1,298 @@
+package curl2
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"os"
+)
+
+// tlsModel maps the `tls` block of the provider and the `tls` attribute of the curl2 data source.
+type tlsModel struct {
+	CACertFile           types.String `tfsdk:"ca_cert_file"`
+	CACertPEM            types.String `tfsdk:"ca_cert_pem"`
+	ClientCertFile       types.String `tfsdk:"client_cert_file"`
+	ClientCertPEM        types.String `tfsdk:"client_cert_pem"`
+	ClientKeyFile        types.String `tfsdk:"client_key_file"`
+	ClientKeyPEM         types.String `tfsdk:"client_key_pem"`
+	ClientPKCS12File     types.String `tfsdk:"client_pkcs12_file"`
+	ClientPKCS12Base64   types.String `tfsdk:"client_pkcs12_base64"`
+	ClientPKCS12Password types.String `tfsdk:"client_pkcs12_password"`
+	MinVersion           types.String `tfsdk:"min_version"`
+	CipherSuites         types.List   `tfsdk:"cipher_suites"`
+	ServerName           types.String `tfsdk:"server_name"`
+}
+
+// tlsOptions is the resolved form of tlsModel. Zero values mean the setting is not configured.
+type tlsOptions struct {
+	caPEM        []byte
+	certificates []tls.Certificate
+	minVersion   uint16
+	cipherSuites []uint16
+	serverName   string
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// tlsOptionsFromModel reads the certificate files and validates the TLS settings.
+func tlsOptionsFromModel(m tlsModel, cipherSuites []string) (tlsOptions, error) {
+	var opts tlsOptions
+
+	caPEM, err := fileOrInline(m.CACertFile, m.CACertPEM, "ca_cert")
+	if err != nil {
+		return opts, err
+	}
+	if len(caPEM) > 0 {
+		if !x509.NewCertPool().AppendCertsFromPEM(caPEM) {
+			return opts, fmt.Errorf("ca_cert does not contain any PEM encoded certificate")
+		}
+		opts.caPEM = caPEM
+	}
+
+	certPEM, err := fileOrInline(m.ClientCertFile, m.ClientCertPEM, "client_cert")
+	if err != nil {
+		return opts, err
+	}
+	keyPEM, err := fileOrInline(m.ClientKeyFile, m.ClientKeyPEM, "client_key")
+	if err != nil {
+		return opts, err
+	}
+	pkcs12Data, err := pkcs12Data(m)
+	if err != nil {
+		return opts, err
+	}
+
+	switch {
+	case len(pkcs12Data) > 0 && (len(certPEM) > 0 || len(keyPEM) > 0):
+		return opts, fmt.Errorf("client_pkcs12 cannot be combined with client_cert and client_key")
+	case len(pkcs12Data) > 0:
+		certs, key, err := azidentity.ParseCertificates(pkcs12Data, []byte(m.ClientPKCS12Password.ValueString()))
+		if err != nil {
+			return opts, fmt.Errorf("unable to parse client_pkcs12: %w", err)
+		}
+		if len(certs) == 0 || key == nil {
+			return opts, fmt.Errorf("client_pkcs12 must contain a certificate and its private key")
+		}
+
+		certificate := tls.Certificate{PrivateKey: key, Leaf: certs[0]}
+		for _, cert := range certs {
+			certificate.Certificate = append(certificate.Certificate, cert.Raw)
+		}
+		opts.certificates = []tls.Certificate{certificate}
+	case len(certPEM) > 0 || len(keyPEM) > 0:
+		certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return opts, fmt.Errorf("unable to load client_cert and client_key: %w", err)
+		}
+		opts.certificates = []tls.Certificate{certificate}
+	}
+
+	if m.MinVersion.ValueString() != "" {
+		version, ok := tlsVersions[m.MinVersion.ValueString()]
+		if !ok {
+			return opts, fmt.Errorf("min_version must be one of 1.0, 1.1, 1.2 or 1.3, got: %s", m.MinVersion.ValueString())
+		}
+		opts.minVersion = version
+	}
+
+	for _, name := range cipherSuites {
+		id, ok := cipherSuiteID(name)
+		if !ok {
+			return opts, fmt.Errorf("unknown cipher suite %q", name)
+		}
+		opts.cipherSuites = append(opts.cipherSuites, id)
+	}
+
+	opts.serverName = m.ServerName.ValueString()
+
+	return opts, nil
+}
+
+// merge returns o with every setting configured in override replacing its own.
+func (o tlsOptions) merge(override tlsOptions) tlsOptions {
+	if len(override.caPEM) > 0 {
+		o.caPEM = override.caPEM
+	}
+	if len(override.certificates) > 0 {
+		o.certificates = override.certificates
+	}
+	if override.minVersion != 0 {
+		o.minVersion = override.minVersion
+	}
+	if len(override.cipherSuites) > 0 {
+		o.cipherSuites = override.cipherSuites
+	}
+	if override.serverName != "" {
+		o.serverName = override.serverName
+	}
+	return o
+}
+
+// digest identifies the settings, so that clients built from equal settings can be shared.
+func (o tlsOptions) digest() string {
+	h := sha256.New()
+	writeField := func(data []byte) {
+		binary.Write(h, binary.BigEndian, uint64(len(data)))
+		h.Write(data)
+	}
+
+	writeField(o.caPEM)
+	for _, certificate := range o.certificates {
+		for _, der := range certificate.Certificate {
+			writeField(der)
+		}
+	}
+	binary.Write(h, binary.BigEndian, o.minVersion)
+	binary.Write(h, binary.BigEndian, o.cipherSuites)
+	writeField([]byte(o.serverName))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// tlsConfig builds the client TLS configuration. A custom CA is trusted in addition to the system roots.
+func (o tlsOptions) tlsConfig(insecure bool) *tls.Config {
+	config := &tls.Config{
+		InsecureSkipVerify: insecure,
+		Certificates:       o.certificates,
+		MinVersion:         o.minVersion,
+		CipherSuites:       o.cipherSuites,
+		ServerName:         o.serverName,
+	}
+
+	if len(o.caPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		pool.AppendCertsFromPEM(o.caPEM)
+		config.RootCAs = pool
+	}
+
+	return config
+}
+
+// fileOrInline returns the contents of file, or inline when no file is given. Setting both is an error.
+func fileOrInline(file types.String, inline types.String, name string) ([]byte, error) {
+	if file.ValueString() != "" && inline.ValueString() != "" {
+		return nil, fmt.Errorf("only one of %s_file or %s_pem can be set", name, name)
+	}
+
+	if file.ValueString() != "" {
+		data, err := os.ReadFile(file.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s_file: %w", name, err)
+		}
+		return data, nil
+	}
+
+	return []byte(inline.ValueString()), nil
+}
+
+func pkcs12Data(m tlsModel) ([]byte, error) {
+	if m.ClientPKCS12File.ValueString() != "" && m.ClientPKCS12Base64.ValueString() != "" {
+		return nil, fmt.Errorf("only one of client_pkcs12_file or client_pkcs12_base64 can be set")
+	}
+
+	if m.ClientPKCS12File.ValueString() != "" {
+		data, err := os.ReadFile(m.ClientPKCS12File.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("unable to read client_pkcs12_file: %w", err)
+		}
+		return data, nil
+	}
+
+	if m.ClientPKCS12Base64.ValueString() != "" {
+		data, err := base64.StdEncoding.DecodeString(m.ClientPKCS12Base64.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode client_pkcs12_base64: %w", err)
+		}
+		return data, nil
+	}
+
+	return nil, nil
+}
+
+func cipherSuiteID(name string) (uint16, bool) {
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name == name {
+			return suite.ID, true
+		}
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		if suite.Name == name {
+			return suite.ID, true
+		}
+	}
+	return 0, false
+}
+
+// tlsAttributeDescriptions documents the attributes shared by the provider `tls` block and the curl2 `tls` attribute.
+var tlsAttributeDescriptions = map[string]string{
+	"ca_cert_file":           "Path to a PEM file with CA certificates to trust in addition to the system roots.",
+	"ca_cert_pem":            "PEM encoded CA certificates to trust in addition to the system roots.",
+	"client_cert_file":       "Path to a PEM encoded client certificate for mutual TLS.",
+	"client_cert_pem":        "PEM encoded client certificate for mutual TLS.",
+	"client_key_file":        "Path to the PEM encoded private key of the client certificate.",
+	"client_key_pem":         "PEM encoded private key of the client certificate.",
+	"client_pkcs12_file":     "Path to a PKCS#12 (PFX) bundle with the client certificate and key, instead of the PEM attributes.",
+	"client_pkcs12_base64":   "Base64 encoded PKCS#12 (PFX) bundle with the client certificate and key, instead of the PEM attributes.",
+	"client_pkcs12_password": "Password of the PKCS#12 bundle.",
+	"min_version":            "Minimum TLS version, one of `1.0`, `1.1`, `1.2` or `1.3`. Defaults to `1.2`.",
+	"cipher_suites":          "Allowed cipher suites by their IANA name, for example `TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256`. Does not apply to TLS 1.3.",
+	"server_name":            "Server name used to verify the certificate and sent via SNI, overriding the host of the URI.",
+}
+
+// tlsSensitiveAttributes lists the tls attributes holding secrets.
+var tlsSensitiveAttributes = map[string]bool{
+	"client_key_pem":         true,
+	"client_pkcs12_base64":   true,
+	"client_pkcs12_password": true,
+}
+
+// tlsOptionsFromObject decodes a `tls` block or attribute. It returns nil when the object is not set.
+func tlsOptionsFromObject(ctx context.Context, object types.Object, attributePath path.Path) (*tlsOptions, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if object.IsNull() || object.IsUnknown() {
+		return nil, diags
+	}
+
+	var m tlsModel
+	diags.Append(object.As(ctx, &m, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	var cipherSuites []string
+	if !m.CipherSuites.IsNull() && !m.CipherSuites.IsUnknown() {
+		diags.Append(m.CipherSuites.ElementsAs(ctx, &cipherSuites, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+	}
+
+	opts, err := tlsOptionsFromModel(m, cipherSuites)
+	if err != nil {
+		diags.AddAttributeError(
+			attributePath,
+			"Invalid TLS Configuration",
+			err.Error(),
+		)
+		return nil, diags
+	}
+
+	return &opts, diags
+}
@@ -0,0 +1,90 @@
+package curl2
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+const defaultTokenMinValidity = 5 * time.Minute
+
+// tokenCache keeps tokens of one provider instance until they are about to expire. It is safe for concurrent
+// use, and concurrent requests for the same key wait for a single token request.
+type tokenCache struct {
+	disabled    bool
+	minValidity time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*tokenCacheEntry
+}
+
+type tokenCacheEntry struct {
+	// mu is held while the token is fetched.
+	mu    sync.Mutex
+	token interface{}
+	// refreshAt is when the token stops being handed out, the minimum validity before its expiry, or the expiry
+	// itself for tokens issued with a shorter lifetime.
+	refreshAt time.Time
+}
+
+func newTokenCache(disabled bool, minValidity time.Duration) *tokenCache {
+	return &tokenCache{
+		disabled:    disabled,
+		minValidity: minValidity,
+		entries:     map[string]*tokenCacheEntry{},
+	}
+}
+
+// tokenCacheKey hashes the credential and token parameters, so secrets are never kept as map keys.
+func tokenCacheKey(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// cachedToken returns the cached token for key while it is valid for at least the minimum validity, otherwise
+// it fetches a new one. Tokens issued for less than the minimum validity are kept until they expire, and tokens
+// without an expiry are not cached. The fetch is detached from the cancellation of ctx, as requests waiting for
+// the same key share its result.
+func cachedToken[T any](ctx context.Context, c *tokenCache, key string, fetch func(context.Context) (T, time.Time, error)) (T, error) {
+	if c == nil || c.disabled {
+		token, _, err := fetch(ctx)
+		return token, err
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &tokenCacheEntry{}
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.token != nil && time.Now().Before(entry.refreshAt) {
+		return entry.token.(T), nil
+	}
+
+	token, expiresAt, err := fetch(context.WithoutCancel(ctx))
+	if err != nil {
+		return token, err
+	}
+	if expiresAt.IsZero() {
+		entry.token = nil
+		return token, nil
+	}
+
+	entry.token = token
+	entry.refreshAt = expiresAt.Add(-c.minValidity)
+	if time.Until(expiresAt) <= c.minValidity {
+		entry.refreshAt = expiresAt
+	}
+	return token, nil
+}
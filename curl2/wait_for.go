@@ -0,0 +1,212 @@
+package curl2
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	defaultWaitInterval    = 5 * time.Second
+	defaultWaitMaxInterval = time.Minute
+	defaultWaitTimeout     = 5 * time.Minute
+)
+
+// waitForModel maps the `wait_for` attribute of the curl2 data source.
+type waitForModel struct {
+	StatusCodes   types.List   `tfsdk:"status_codes"`
+	JSONPath      types.String `tfsdk:"json_path"`
+	JSONValue     types.String `tfsdk:"json_value"`
+	BodyRegex     types.String `tfsdk:"body_regex"`
+	IntervalMS    types.Int64  `tfsdk:"interval_ms"`
+	MaxIntervalMS types.Int64  `tfsdk:"max_interval_ms"`
+	Backoff       types.String `tfsdk:"backoff"`
+	TimeoutMS     types.Int64  `tfsdk:"timeout_ms"`
+}
+
+// waitForConfig is the resolved form of waitForModel with defaults applied.
+type waitForConfig struct {
+	statusCodes []string
+	jsonPath    string
+	jsonValue   *string
+	bodyRegex   *regexp.Regexp
+	interval    time.Duration
+	maxInterval time.Duration
+	backoff     string
+	timeout     time.Duration
+}
+
+// waitForConfigFromObject decodes and validates a `wait_for` attribute. It returns nil when it is not set.
+func waitForConfigFromObject(ctx context.Context, object types.Object) (*waitForConfig, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if object.IsNull() || object.IsUnknown() {
+		return nil, diags
+	}
+
+	var m waitForModel
+	diags.Append(object.As(ctx, &m, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	config := &waitForConfig{
+		jsonPath:    m.JSONPath.ValueString(),
+		interval:    durationOrDefault(m.IntervalMS, defaultWaitInterval),
+		maxInterval: durationOrDefault(m.MaxIntervalMS, defaultWaitMaxInterval),
+		backoff:     stringOrDefault(m.Backoff, "constant"),
+		timeout:     durationOrDefault(m.TimeoutMS, defaultWaitTimeout),
+	}
+	if !m.JSONValue.IsNull() && !m.JSONValue.IsUnknown() {
+		value := m.JSONValue.ValueString()
+		config.jsonValue = &value
+	}
+	if !m.StatusCodes.IsNull() && !m.StatusCodes.IsUnknown() {
+		diags.Append(m.StatusCodes.ElementsAs(ctx, &config.statusCodes, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+	}
+
+	err := config.validate()
+	if err == nil && m.BodyRegex.ValueString() != "" {
+		config.bodyRegex, err = regexp.Compile(m.BodyRegex.ValueString())
+	}
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("wait_for"),
+			"Invalid Wait For Configuration",
+			err.Error(),
+		)
+		return nil, diags
+	}
+
+	return config, diags
+}
+
+func (w *waitForConfig) validate() error {
+	for _, pattern := range w.statusCodes {
+		if _, _, err := parseStatusPattern(pattern); err != nil {
+			return err
+		}
+	}
+	if w.jsonValue != nil && w.jsonPath == "" {
+		return fmt.Errorf("json_path must be set along with json_value")
+	}
+	if w.backoff != backoffExponential && w.backoff != "constant" {
+		return fmt.Errorf("backoff must be one of constant or exponential, got: %s", w.backoff)
+	}
+	if w.interval <= 0 || w.timeout <= 0 {
+		return fmt.Errorf("interval_ms and timeout_ms must be positive")
+	}
+	return nil
+}
+
+// unmetCondition returns a description of the first condition the response does not meet, or an empty string
+// when all of them hold. Without status codes, any 2xx status is expected.
+func (w *waitForConfig) unmetCondition(r *http.Response, body []byte) string {
+	statusCodes := w.statusCodes
+	if len(statusCodes) == 0 {
+		statusCodes = []string{"2xx"}
+	}
+	if matched, _ := statusMatches(r.StatusCode, statusCodes); !matched {
+		return fmt.Sprintf("status %d is not one of [%s]", r.StatusCode, strings.Join(statusCodes, ", "))
+	}
+
+	if w.jsonPath != "" {
+		result, found, err := lookupJSON(body, w.jsonPath)
+		switch {
+		case err != nil:
+			return err.Error()
+		case !found:
+			return fmt.Sprintf("json_path %q was not found", w.jsonPath)
+		case w.jsonValue != nil && jsonResultString(result) != *w.jsonValue:
+			return fmt.Sprintf("json_path %q is %q, expected %q", w.jsonPath, jsonResultString(result), *w.jsonValue)
+		}
+	}
+
+	if w.bodyRegex != nil && !w.bodyRegex.Match(body) {
+		return fmt.Sprintf("body does not match %q", w.bodyRegex.String())
+	}
+
+	return ""
+}
+
+// nextInterval returns the delay before the next attempt.
+func (w *waitForConfig) nextInterval(current time.Duration) time.Duration {
+	if w.backoff != backoffExponential {
+		return current
+	}
+	next := current * 2
+	if next > w.maxInterval {
+		return w.maxInterval
+	}
+	return next
+}
+
+// waitFor sends the request until the response meets the wait_for conditions or the deadline passes. Requests
+// failing in transport, for example while the service is not reachable yet, are retried like unmet conditions.
+// Requests are bound to the deadline, so a slow attempt cannot outlast timeout_ms.
+func (p *preparedRequest) waitFor(ctx context.Context, waitFor *waitForConfig, uri string) (*http.Response, []byte, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	deadline := time.Now().Add(waitFor.timeout)
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	interval := waitFor.interval
+	for attempt := 1; ; attempt++ {
+		newReq, reqDiags := p.newRequest(ctx, uri)
+		if reqDiags.HasError() {
+			return nil, nil, reqDiags
+		}
+
+		var unmet, last string
+		r, responseData, err := p.client.Do(newReq)
+		if err != nil {
+			unmet = "the request failed"
+			last = "Last error: " + err.Error()
+		} else {
+			unmet = waitFor.unmetCondition(r, responseData)
+			if unmet == "" {
+				return r, responseData, reqDiags
+			}
+			last = "Last response: " + unexpectedStatusDetail(r, responseData, nil)
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			detail := fmt.Sprintf("The condition was not met after %d attempts in %s: %s.\n\n%s", attempt, waitFor.timeout, unmet, last)
+			diags.AddAttributeError(
+				path.Root("wait_for"),
+				"Timed out waiting for condition",
+				detail,
+			)
+			return nil, nil, diags
+		}
+
+		select {
+		case <-ctx.Done():
+			diags.AddError(
+				"Timed out waiting for condition",
+				fmt.Sprintf("%s after %d attempts, the last one failed because %s.\n\n%s", ctx.Err(), attempt, unmet, last),
+			)
+			return nil, nil, diags
+		case <-time.After(interval):
+		}
+		interval = waitFor.nextInterval(interval)
+	}
+}
+
+// durationOrDefault converts a millisecond attribute, returning fallback when it is not set.
+func durationOrDefault(value types.Int64, fallback time.Duration) time.Duration {
+	if value.IsNull() || value.IsUnknown() {
+		return fallback
+	}
+	return time.Duration(value.ValueInt64()) * time.Millisecond
+}
@@ -20,12 +20,17 @@ provider "curl2" {
 data "curl2" "getPosts" {
   http_method = "GET"
   uri = "https://jsonplaceholder.typicode.com/posts"
+  #  expected_status_codes = ["2xx"]
   #  auth_type = "Basic"
   #  basic_auth_username = "<UserName>"
   #  basic_auth_password = "<Password>"
   #  headers = {
   #    Accept = "*/*"
   #  }
+  #  timeouts = {
+  #    connect_ms = 2000
+  #    total_ms = 10000
+  #  }
 }
 
 output "all_posts_response" {
@@ -36,12 +41,44 @@ output "all_posts_status" {
   value = data.curl2.getPosts.response.status_code
 }
 
+data "curl2" "getPost" {
+  http_method = "GET"
+  uri = "https://jsonplaceholder.typicode.com/posts/1"
+  extract = {
+    title = "$.title"
+    user_id = "userId"
+    tags = "$.tags"
+  }
+  extract_optional = ["tags"]
+}
+
+output "post_title" {
+  value = data.curl2.getPost.response.extracted.title
+}
+
+output "all_posts_content_type" {
+  value = data.curl2.getPosts.response.headers["Content-Type"][0]
+}
+
 data "curl2" "postPosts" {
   http_method = "POST"
   uri = "https://jsonplaceholder.typicode.com/posts"
   json = "{\"title\":\"foo\",\"body\":\"bar\",\"userId\":\"1\"}" //need the json in string format
   #  auth_type = "Bearer"
   #  bearer_token = "<Any Bearer Token>"
+  #  auth_type = "AzureAD" // uses the provider azure_ad block
+  #  token_scopes = ["api://<APP_ID>/.default"]
+  #  auth_type = "AWSv4"
+  #  aws_sigv4 = {
+  #    region = "eu-west-1" // or ENV AWS_REGION
+  #    service = "execute-api"
+  #  }
+  #  auth_type = "HMAC"
+  #  hmac = {
+  #    key = "<Shared Secret>"
+  #    signed_components = ["method", "path", "timestamp", "body_digest"]
+  #    encoding = "base64"
+  #  }
   #  headers = {
   #    Accept = "*/*"
   #    Content-Type = "application/json"
@@ -50,4 +87,54 @@ data "curl2" "postPosts" {
 
 output "post_posts_output" {
   value = data.curl2.postPosts.response
-}
\ No newline at end of file
+}
+
+data "curl2" "postForm" {
+  http_method = "POST"
+  uri = "https://httpbin.org/post"
+  form = {
+    grant_type = "client_credentials"
+    scope = "read"
+  }
+  #  multipart = {
+  #    fields = { description = "report" }
+  #    files = { upload = "./report.pdf" }
+  #  }
+  #  raw_body = "<note>hello</note>"
+  #  content_type = "application/xml"
+}
+
+data "curl2" "allRepos" {
+  http_method = "GET"
+  uri = "https://api.github.com/orgs/hashicorp/repos"
+  pagination = {
+    strategy = "link_header" // next_url, cursor, page or offset
+    items_path = "$"
+    max_pages = 20
+  }
+}
+
+output "all_repo_names" {
+  value = [for repo in jsondecode(data.curl2.allRepos.items) : repo.name]
+}
+
+data "curl2" "waitForReady" {
+  http_method = "GET"
+  uri = "https://api.example.com/clusters/1/status"
+  wait_for = {
+    status_codes = ["200"]
+    json_path = "$.state"
+    json_value = "Ready"
+    interval_ms = 2000
+    backoff = "exponential"
+    timeout_ms = 600000
+  }
+}
+
+data "curl2" "fromCurl" {
+  curl_command = <<-EOT
+    curl -X POST https://httpbin.org/post \
+      -H 'Content-Type: application/json' \
+      -d '{"title": "foo", "userId": 1}'
+  EOT
+}
@@ -16,9 +16,11 @@ provider "curl2" {
 }
 
 data "curl2_auth0_token" auth0Token {
-  audience = "https://xyx.fy"
+  audience     = "https://xyx.fy"
+  organization = "org_123" //Optional
 }
 
 output "auth_token" {
   value = data.curl2_auth0_token.auth0Token.response
+  sensitive = true
 }
@@ -9,6 +9,7 @@ terraform {
 
 provider "curl2" {
   azure_ad {
+    credential_type = "ClientSecret" //ClientCertificate, WorkloadIdentity or ManagedIdentity
     client_id = "<AZURE_CLIENT_ID>" //You can also set ENV AZURE_CLIENT_ID
     client_secret = "<AZURE_CLIENT_SECRET>" //You can also set ENV AZURE_CLIENT_SECRET
     tenant_id = "<AZURE_TENANT_ID>" //You can also set ENV AZURE_TENANT_ID
@@ -21,4 +22,5 @@ data "curl2_azuread_token" azureADToken {
 
 output "azure_ad_token" {
   value = data.curl2_azuread_token.azureADToken.response
+  sensitive = true
 }
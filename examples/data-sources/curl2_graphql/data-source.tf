@@ -0,0 +1,38 @@
+terraform {
+  required_providers {
+    curl2 = {
+      source = "mehulgohil/curl2"
+      version = "1.6.1"
+    }
+  }
+}
+
+provider "curl2" {}
+
+data "curl2_graphql" "repository" {
+  endpoint = "https://api.github.com/graphql"
+  auth_type = "Bearer"
+  bearer_token = var.github_token
+
+  query = <<-EOT
+    query Repository($owner: String!, $name: String!) {
+      repository(owner: $owner, name: $name) {
+        stargazerCount
+      }
+    }
+  EOT
+
+  variables = {
+    owner = "hashicorp"
+    name = "terraform"
+  }
+}
+
+variable "github_token" {
+  type = string
+  sensitive = true
+}
+
+output "stars" {
+  value = jsondecode(data.curl2_graphql.repository.data).repository.stargazerCount
+}
@@ -0,0 +1,30 @@
+terraform {
+  required_providers {
+    curl2 = {
+      source = "mehulgohil/curl2"
+      version = "1.6.1"
+    }
+  }
+}
+
+provider "curl2" {}
+
+data "curl2_oauth2_token" "keycloak" {
+  token_url = "https://keycloak.example.com/realms/main/protocol/openid-connect/token"
+  client_id = "<CLIENT_ID>"
+  client_secret = "<CLIENT_SECRET>"
+  scopes = ["profile", "email"]
+  #  audience = "https://api.example.com"
+  #  client_auth_style = "body"
+  #  grant_type = "password"
+  #  username = "<USERNAME>"
+  #  password = "<PASSWORD>"
+  #  extra_params = {
+  #    resource = "https://api.example.com"
+  #  }
+}
+
+output "oauth2_token" {
+  value = data.curl2_oauth2_token.keycloak.response
+  sensitive = true
+}
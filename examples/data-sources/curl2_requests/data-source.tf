@@ -0,0 +1,30 @@
+terraform {
+  required_providers {
+    curl2 = {
+      source = "mehulgohil/curl2"
+      version = "1.6.1"
+    }
+  }
+}
+
+provider "curl2" {}
+
+locals {
+  services = ["orders", "payments", "users"]
+}
+
+data "curl2_requests" "health" {
+  concurrency = 20
+  fail_fast = false // report failures in responses[*].error instead of failing the read
+
+  requests = {
+    for service in local.services : service => {
+      uri = "https://${service}.example.com/health"
+      expected_status_codes = ["2xx"]
+    }
+  }
+}
+
+output "unhealthy_services" {
+  value = [for name, response in data.curl2_requests.health.responses : name if response.error != null]
+}
@@ -0,0 +1,31 @@
+terraform {
+  required_version = ">= 1.10"
+  required_providers {
+    curl2 = {
+      source = "mehulgohil/curl2"
+      version = "1.6.1"
+    }
+  }
+}
+
+provider "curl2" {}
+
+ephemeral "curl2" "dbPassword" {
+  http_method = "GET"
+  uri = "https://vault.example.com/v1/secret/data/db"
+  headers = {
+    X-Vault-Token = var.vault_token
+  }
+  expected_status_codes = ["200"]
+  extract = {
+    password = "$.data.data.password"
+  }
+}
+
+// Ephemeral values can only be used in provider configurations, write-only arguments
+// and other ephemeral resources, and are never written to the plan or state.
+provider "postgresql" {
+  host = "db.example.com"
+  username = "admin"
+  password = ephemeral.curl2.dbPassword.response.extracted["password"]
+}
@@ -0,0 +1,29 @@
+terraform {
+  required_version = ">= 1.10"
+  required_providers {
+    curl2 = {
+      source = "mehulgohil/curl2"
+      version = "1.6.1"
+    }
+  }
+}
+
+provider "curl2" {
+  auth0 {
+    client_id = "<AUTH0_CLIENT_ID>" //You can also set ENV AUTH0_CLIENT_ID
+    client_secret = "<AUTH0_CLIENT_SECRET>" //You can also set ENV AUTH0_CLIENT_SECRET
+    domain = "<AUTH0_DOMAIN>" //You can also set ENV AUTH0_DOMAIN
+  }
+}
+
+ephemeral "curl2_auth0_token" "auth0Token" {
+  audience = "https://xyz.com"
+}
+
+// The token is never written to the plan or state.
+ephemeral "curl2" "orders" {
+  http_method = "GET"
+  uri = "https://xyz.com/orders"
+  auth_type = "Bearer"
+  bearer_token = ephemeral.curl2_auth0_token.auth0Token.response.token
+}
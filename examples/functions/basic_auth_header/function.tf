@@ -0,0 +1,17 @@
+terraform {
+  required_version = ">= 1.8"
+  required_providers {
+    curl2 = {
+      source = "mehulgohil/curl2"
+      version = "1.6.1"
+    }
+  }
+}
+
+data "curl2" "getPosts" {
+  http_method = "GET"
+  uri = "https://example.com/api/posts"
+  headers = {
+    Authorization = provider::curl2::basic_auth_header("<UserName>", "<Password>")
+  }
+}
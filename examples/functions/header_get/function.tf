@@ -0,0 +1,18 @@
+terraform {
+  required_version = ">= 1.8"
+  required_providers {
+    curl2 = {
+      source = "mehulgohil/curl2"
+      version = "1.6.1"
+    }
+  }
+}
+
+data "curl2" "getPosts" {
+  http_method = "GET"
+  uri = "https://jsonplaceholder.typicode.com/posts"
+}
+
+output "content_type" {
+  value = provider::curl2::header_get(data.curl2.getPosts.response.headers, "content-type")
+}
@@ -0,0 +1,18 @@
+terraform {
+  required_version = ">= 1.8"
+  required_providers {
+    curl2 = {
+      source = "mehulgohil/curl2"
+      version = "1.6.1"
+    }
+  }
+}
+
+data "curl2" "getPost" {
+  http_method = "GET"
+  uri = "https://jsonplaceholder.typicode.com/posts/1"
+}
+
+output "post_title" {
+  value = provider::curl2::jsonpath(data.curl2.getPost.response.body, "$.title")
+}
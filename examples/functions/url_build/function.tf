@@ -0,0 +1,17 @@
+terraform {
+  required_version = ">= 1.8"
+  required_providers {
+    curl2 = {
+      source = "mehulgohil/curl2"
+      version = "1.6.1"
+    }
+  }
+}
+
+# https://example.com/api/users/john%20doe/posts?sort=desc&tag=a%26b
+output "posts_url" {
+  value = provider::curl2::url_build("https://example.com/api", "users/john doe/posts", {
+    tag  = "a&b"
+    sort = "desc"
+  })
+}
@@ -10,13 +10,26 @@ terraform {
 provider "curl2" {
   #  disable_tls = true
   #  timeout_ms = 500
+  #  har_file = "./curl2.har"
   #  retry {
   #    retry_attempts = 5
   #    min_delay_ms = 5
   #    max_delay_ms = 10
+  #    retryable_status_codes = [429, 502, 503]
+  #    backoff = "exponential"
+  #    jitter = true
+  #    idempotent_only = true
+  #  }
+
+  #  tls {
+  #    ca_cert_file = "./internal-ca.pem"
+  #    client_cert_file = "./client.pem"
+  #    client_key_file = "./client-key.pem"
+  #    min_version = "1.2"
   #  }
 
   #  azure_ad {
+  #    credential_type = "ClientSecret"
   #    client_id = "<AZURE_CLIENT_ID>"
   #    client_secret = "<AZURE_CLIENT_SECRET>"
   #    tenant_id = "<AZURE_TENANT_ID>"
@@ -27,4 +40,20 @@ provider "curl2" {
   #    client_secret = "<AUTH0_CLIENT_SECRET>"
   #    domain = "<AUTH0_DOMAIN>"
   #  }
+
+  #  oauth2 {
+  #    token_url = "<TOKEN_URL>"
+  #    client_id = "<CLIENT_ID>"
+  #    client_secret = "<CLIENT_SECRET>"
+  #  }
+
+  #  token_cache {
+  #    min_validity_seconds = 300
+  #  }
+
+  #  logging {
+  #    mask_headers = ["X-Internal-*"]
+  #    mask_json_paths = ["$.data.ssn"]
+  #    max_body_bytes = 2048
+  #  }
 }
\ No newline at end of file
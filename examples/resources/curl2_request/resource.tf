@@ -0,0 +1,47 @@
+terraform {
+  required_providers {
+    curl2 = {
+      source = "mehulgohil/curl2"
+      version = "1.6.1"
+    }
+  }
+}
+
+provider "curl2" {}
+
+resource "curl2_request" "post" {
+  #  auth_type = "Bearer"
+  #  bearer_token = "<Any Bearer Token>"
+
+  create {
+    http_method = "POST"
+    uri = "https://jsonplaceholder.typicode.com/posts"
+    body = jsonencode({ title = "foo", body = "bar", userId = 1 })
+    headers = {
+      Content-Type = "application/json"
+    }
+  }
+
+  read {
+    http_method = "GET"
+    uri = "https://jsonplaceholder.typicode.com/posts/1"
+  }
+
+  update {
+    http_method = "PUT"
+    uri = "https://jsonplaceholder.typicode.com/posts/1"
+    body = jsonencode({ id = 1, title = "foo", body = "bar", userId = 1 })
+    headers = {
+      Content-Type = "application/json"
+    }
+  }
+
+  destroy {
+    http_method = "DELETE"
+    uri = "https://jsonplaceholder.typicode.com/posts/1"
+  }
+}
+
+output "created_post" {
+  value = jsondecode(curl2_request.post.response.body)
+}